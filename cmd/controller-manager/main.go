@@ -68,6 +68,10 @@ func main() {
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
+	if err := cliCfg.Validate(); err != nil {
+		klog.Fatalf("invalid flags: %v", err)
+	}
+
 	version.LogVersionInfo()
 	flag.VisitAll(func(flag *flag.Flag) {
 		klog.V(1).Infof("FLAG: --%s=%q", flag.Name, flag.Value)
@@ -148,21 +152,26 @@ func main() {
 			WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 		}
 
-		// Initialize all controllers
-		controllers := []Controller{
-			tidbcluster.NewController(deps),
-			dmcluster.NewController(deps),
-			backup.NewController(deps),
-			restore.NewController(deps),
-			backupschedule.NewController(deps),
-			tidbinitializer.NewController(deps),
-			tidbmonitor.NewController(deps),
+		// Initialize all controllers, keyed by the names used in --controller-workers
+		controllers := map[string]Controller{
+			"tidbcluster":     tidbcluster.NewController(deps),
+			"dmcluster":       dmcluster.NewController(deps),
+			"backup":          backup.NewController(deps),
+			"restore":         restore.NewController(deps),
+			"backupschedule":  backupschedule.NewController(deps),
+			"tidbinitializer": tidbinitializer.NewController(deps),
+			"tidbmonitor":     tidbmonitor.NewController(deps),
 		}
 		if cliCfg.PodWebhookEnabled {
-			controllers = append(controllers, periodicity.NewController(deps))
+			controllers["periodicity"] = periodicity.NewController(deps)
 		}
 		if features.DefaultFeatureGate.Enabled(features.AutoScaling) {
-			controllers = append(controllers, autoscaler.NewController(deps))
+			controllers["tidbclusterautoscaler"] = autoscaler.NewController(deps)
+		}
+		for name := range cliCfg.ControllerWorkers {
+			if _, ok := controllers[name]; !ok {
+				klog.Fatalf("invalid --controller-workers: controller %s is unknown or not enabled", name)
+			}
 		}
 
 		// Start informer factories after all controllers are initialized.
@@ -182,9 +191,10 @@ func main() {
 		klog.Info("cache of informer factories sync successfully")
 
 		// Start syncLoop for all controllers
-		for _, controller := range controllers {
+		for name, controller := range controllers {
 			c := controller
-			go wait.Forever(func() { c.Run(cliCfg.Workers, ctx.Done()) }, cliCfg.WaitDuration)
+			workers := cliCfg.WorkersOf(name)
+			go wait.Forever(func() { c.Run(workers, ctx.Done()) }, cliCfg.WaitDuration)
 		}
 	}
 	onStopped := func() {
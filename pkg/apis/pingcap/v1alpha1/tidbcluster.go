@@ -16,6 +16,9 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +39,12 @@ const (
 	defaultEnablePVReclaim    = false
 	// defaultEvictLeaderTimeout is the timeout limit of evict leader
 	defaultEvictLeaderTimeout = 10 * time.Minute
+	// defaultTiCDCGracefulShutdownTimeout is the timeout limit of draining the ticdc capture
+	defaultTiCDCGracefulShutdownTimeout = 10 * time.Minute
+	// defaultPumpOfflineTimeout is the timeout limit of taking the pump node offline
+	defaultPumpOfflineTimeout = 10 * time.Minute
+	// tikvSlowStoreScore is the slow score from which PD regards a store as slow
+	tikvSlowStoreScore = 100
 )
 
 var (
@@ -63,13 +72,14 @@ func (tc *TidbCluster) PDImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
-	return image
+	return tc.imageWithRegistry(image)
 }
 
 func (tc *TidbCluster) PDVersion() string {
 	image := tc.PDImage()
 	colonIdx := strings.LastIndexByte(image, ':')
-	if colonIdx >= 0 {
+	// the colon before the last slash is the port of the registry
+	if colonIdx >= 0 && colonIdx > strings.LastIndexByte(image, '/') {
 		return image[colonIdx+1:]
 	}
 
@@ -91,13 +101,14 @@ func (tc *TidbCluster) TiKVImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
-	return image
+	return tc.imageWithRegistry(image)
 }
 
 func (tc *TidbCluster) TiKVVersion() string {
 	image := tc.TiKVImage()
 	colonIdx := strings.LastIndexByte(image, ':')
-	if colonIdx >= 0 {
+	// the colon before the last slash is the port of the registry
+	if colonIdx >= 0 && colonIdx > strings.LastIndexByte(image, '/') {
 		return image[colonIdx+1:]
 	}
 
@@ -122,6 +133,126 @@ func (tc *TidbCluster) TiKVEvictLeaderTimeout() time.Duration {
 	return defaultEvictLeaderTimeout
 }
 
+// TiKVUpdatePartition returns the minimum ordinal of TiKV Pods to be updated
+func (tc *TidbCluster) TiKVUpdatePartition() int32 {
+	if tc.Spec.TiKV.UpdatePartition != nil && *tc.Spec.TiKV.UpdatePartition > 0 {
+		return *tc.Spec.TiKV.UpdatePartition
+	}
+	return 0
+}
+
+// TiKVPreUpgradeHook returns the container run before TiKV Pods are upgraded, nil if not set
+func (tc *TidbCluster) TiKVPreUpgradeHook() *corev1.Container {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.UpgradeHooks == nil {
+		return nil
+	}
+	return tc.Spec.TiKV.UpgradeHooks.PreUpgrade
+}
+
+// TiKVPostUpgradeHook returns the container run after TiKV Pods are upgraded, nil if not set
+func (tc *TidbCluster) TiKVPostUpgradeHook() *corev1.Container {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.UpgradeHooks == nil {
+		return nil
+	}
+	return tc.Spec.TiKV.UpgradeHooks.PostUpgrade
+}
+
+// TiKVSlowStoreProtectionEnabled returns whether to evict the leaders of slow TiKV stores
+func (tc *TidbCluster) TiKVSlowStoreProtectionEnabled() bool {
+	return tc.Spec.TiKV != nil && tc.Spec.TiKV.SlowStoreProtection != nil && *tc.Spec.TiKV.SlowStoreProtection
+}
+
+// TiKVSlowStores returns the TiKV stores that are reported as slow by PD
+func (tc *TidbCluster) TiKVSlowStores() []TiKVStore {
+	var stores []TiKVStore
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.IsSlow() {
+			stores = append(stores, store)
+		}
+	}
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].PodName < stores[j].PodName
+	})
+	return stores
+}
+
+// IsSlow returns whether the Up store is reported as slow by PD
+func (s *TiKVStore) IsSlow() bool {
+	return s.State == TiKVStateUp && s.SlowScore >= tikvSlowStoreScore
+}
+
+// TiKVHighDiskUsageStores returns the Up TiKV stores whose disk usage exceeds
+// spec.tikv.upgradeDiskUsageThreshold, it returns nil if the threshold is not set
+func (tc *TidbCluster) TiKVHighDiskUsageStores() []TiKVStore {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.UpgradeDiskUsageThreshold == nil {
+		return nil
+	}
+	threshold := *tc.Spec.TiKV.UpgradeDiskUsageThreshold
+	var stores []TiKVStore
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State == TiKVStateUp && store.DiskUsagePercent > threshold {
+			stores = append(stores, store)
+		}
+	}
+	sort.Slice(stores, func(i, j int) bool {
+		return stores[i].PodName < stores[j].PodName
+	})
+	return stores
+}
+
+// TiKVStoresWarmedUp returns whether the region count of every Up TiKV store reaches
+// spec.tikv.warmUpRegionPercent of the average region count of all the Up stores,
+// it returns true if the percent is not set
+func (tc *TidbCluster) TiKVStoresWarmedUp() bool {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.WarmUpRegionPercent == nil {
+		return true
+	}
+	percent := int64(*tc.Spec.TiKV.WarmUpRegionPercent)
+	var upStores, totalRegions int64
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State == TiKVStateUp {
+			upStores++
+			totalRegions += int64(store.RegionCount)
+		}
+	}
+	if upStores == 0 {
+		return true
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State != TiKVStateUp {
+			continue
+		}
+		if int64(store.RegionCount)*100*upStores < totalRegions*percent {
+			return false
+		}
+	}
+	return true
+}
+
+// GetLeaderWeight returns the leader weight of the stores, it defaults to 1
+func (w *StoreWeight) GetLeaderWeight() (float64, error) {
+	return parseStoreWeight(w.Leader)
+}
+
+// GetRegionWeight returns the region weight of the stores, it defaults to 1
+func (w *StoreWeight) GetRegionWeight() (float64, error) {
+	return parseStoreWeight(w.Region)
+}
+
+func parseStoreWeight(weight *string) (float64, error) {
+	if weight == nil {
+		return 1, nil
+	}
+	v, err := strconv.ParseFloat(*weight, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("store weight %s is not a positive number", *weight)
+	}
+	return v, nil
+}
+
 func (tc *TidbCluster) TiFlashImage() string {
 	image := tc.Spec.TiFlash.Image
 	baseImage := tc.Spec.TiFlash.BaseImage
@@ -137,7 +268,7 @@ func (tc *TidbCluster) TiFlashImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
-	return image
+	return tc.imageWithRegistry(image)
 }
 
 func (tc *TidbCluster) TiCDCImage() string {
@@ -155,7 +286,7 @@ func (tc *TidbCluster) TiCDCImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
-	return image
+	return tc.imageWithRegistry(image)
 }
 
 func (tc *TidbCluster) TiFlashContainerPrivilege() *bool {
@@ -166,6 +297,13 @@ func (tc *TidbCluster) TiFlashContainerPrivilege() *bool {
 	return tc.Spec.TiFlash.Privileged
 }
 
+// TiFlashConfigReloaderEnabled returns whether the reloadable sections of the TiFlash config
+// are updated in place and reloaded by the config-reloader sidecar
+func (tc *TidbCluster) TiFlashConfigReloaderEnabled() bool {
+	return tc.Spec.TiFlash != nil && tc.Spec.TiFlash.ConfigReloader != nil && tc.Spec.TiFlash.ConfigReloader.Enabled &&
+		tc.BaseTiFlashSpec().ConfigUpdateStrategy() == ConfigUpdateStrategyRollingUpdate
+}
+
 func (tc *TidbCluster) TiDBImage() string {
 	image := tc.Spec.TiDB.Image
 	baseImage := tc.Spec.TiDB.BaseImage
@@ -181,7 +319,7 @@ func (tc *TidbCluster) TiDBImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
-	return image
+	return tc.imageWithRegistry(image)
 }
 
 func (tc *TidbCluster) PumpImage() *string {
@@ -202,6 +340,7 @@ func (tc *TidbCluster) PumpImage() *string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
+	image = tc.imageWithRegistry(image)
 	return &image
 }
 
@@ -212,9 +351,25 @@ func (tc *TidbCluster) HelperImage() string {
 		image = tc.Spec.TiDB.GetSlowLogTailerSpec().Image
 	}
 	if image == nil {
-		return defaultHelperImage
+		return tc.imageWithRegistry(defaultHelperImage)
 	}
-	return *image
+	return tc.imageWithRegistry(*image)
+}
+
+// imageWithRegistry prefixes the image with spec.imageRegistry if the image does not specify a registry
+func (tc *TidbCluster) imageWithRegistry(image string) string {
+	registry := strings.TrimSuffix(tc.Spec.ImageRegistry, "/")
+	if registry == "" || image == "" {
+		return image
+	}
+	if i := strings.IndexByte(image, '/'); i >= 0 {
+		// the same rule as docker to tell whether the first component is a registry host
+		domain := image[:i]
+		if domain == "localhost" || strings.ContainsAny(domain, ".:") {
+			return image
+		}
+	}
+	return registry + "/" + image
 }
 
 func (tc *TidbCluster) HelperImagePullPolicy() corev1.PullPolicy {
@@ -272,6 +427,36 @@ func (tc *TidbCluster) TiFlashUpgrading() bool {
 	return tc.Status.TiFlash.Phase == UpgradePhase
 }
 
+// TiDBSuspended returns whether TiDB is suspended by spec.tidb.suspend
+func (tc *TidbCluster) TiDBSuspended() bool {
+	return tc.Spec.TiDB != nil && tc.Spec.TiDB.Suspend
+}
+
+// TiFlashSuspended returns whether TiFlash is suspended by spec.tiflash.suspend
+func (tc *TidbCluster) TiFlashSuspended() bool {
+	return tc.Spec.TiFlash != nil && tc.Spec.TiFlash.Suspend
+}
+
+// TiCDCSuspended returns whether TiCDC is suspended by spec.ticdc.suspend
+func (tc *TidbCluster) TiCDCSuspended() bool {
+	return tc.Spec.TiCDC != nil && tc.Spec.TiCDC.Suspend
+}
+
+// SuspendedComponents returns the components suspended by the suspend field of their specs
+func (tc *TidbCluster) SuspendedComponents() []MemberType {
+	var components []MemberType
+	if tc.TiDBSuspended() {
+		components = append(components, TiDBMemberType)
+	}
+	if tc.TiFlashSuspended() {
+		components = append(components, TiFlashMemberType)
+	}
+	if tc.TiCDCSuspended() {
+		components = append(components, TiCDCMemberType)
+	}
+	return components
+}
+
 func (tc *TidbCluster) getDeleteSlots(component string) (deleteSlots sets.Int32) {
 	deleteSlots = sets.NewInt32()
 	annotations := tc.GetAnnotations()
@@ -426,6 +611,28 @@ func (tc *TidbCluster) TiFlashStsDesiredReplicas() int32 {
 	return tc.Spec.TiFlash.Replicas + int32(len(tc.Status.TiFlash.FailureStores))
 }
 
+// TiCDCGracefulShutdownTimeout returns the timeout of draining the ticdc capture
+func (tc *TidbCluster) TiCDCGracefulShutdownTimeout() time.Duration {
+	if tc.Spec.TiCDC != nil && tc.Spec.TiCDC.GracefulShutdownTimeout != nil {
+		d, err := time.ParseDuration(*tc.Spec.TiCDC.GracefulShutdownTimeout)
+		if err == nil {
+			return d
+		}
+	}
+	return defaultTiCDCGracefulShutdownTimeout
+}
+
+// PumpOfflineTimeout returns the timeout of taking the pump node offline
+func (tc *TidbCluster) PumpOfflineTimeout() time.Duration {
+	if tc.Spec.Pump != nil && tc.Spec.Pump.OfflineTimeout != nil {
+		d, err := time.ParseDuration(*tc.Spec.Pump.OfflineTimeout)
+		if err == nil {
+			return d
+		}
+	}
+	return defaultPumpOfflineTimeout
+}
+
 func (tc *TidbCluster) TiCDCDeployDesiredReplicas() int32 {
 	if tc.Spec.TiCDC == nil {
 		return 0
@@ -600,6 +807,10 @@ func (tc *TidbCluster) IsPVReclaimEnabled() bool {
 	return *enabled
 }
 
+func (tc *TidbCluster) IsPodDisruptionBudgetEnabled() bool {
+	return tc.Spec.EnablePodDisruptionBudget != nil && *tc.Spec.EnablePodDisruptionBudget
+}
+
 func (tc *TidbCluster) IsTiDBBinlogEnabled() bool {
 	binlogEnabled := tc.Spec.TiDB.BinlogEnabled
 	if binlogEnabled == nil {
@@ -613,6 +824,19 @@ func (tidb *TiDBSpec) IsTLSClientEnabled() bool {
 	return tidb.TLSClient != nil && tidb.TLSClient.Enabled
 }
 
+// TLSClientRotationPolicy returns how the rotated TiDB server certificate is applied,
+// it's empty if TLS between TiDB server and MySQL client is not enabled.
+func (tidb *TiDBSpec) TLSClientRotationPolicy() TLSCertRotationPolicy {
+	if !tidb.IsTLSClientEnabled() {
+		return ""
+	}
+	return tidb.TLSClient.RotationPolicy
+}
+
+func (tidb *TiDBSpec) IsCPUPinningEnabled() bool {
+	return tidb.CPUPinning != nil && tidb.CPUPinning.Enabled
+}
+
 func (tidb *TiDBSpec) ShouldSeparateSlowLog() bool {
 	separateSlowLog := tidb.SeparateSlowLog
 	if separateSlowLog == nil {
@@ -644,6 +868,10 @@ func (tikv *TiKVSpec) ShouldSeparateRaftLog() bool {
 	return *separateRaftLog
 }
 
+func (tikv *TiKVSpec) IsCPUPinningEnabled() bool {
+	return tikv.CPUPinning != nil && tikv.CPUPinning.Enabled
+}
+
 func (tikv *TiKVSpec) GetLogTailerSpec() LogTailerSpec {
 	if tikv.LogTailer == nil {
 		return defaultLogTailerSpec
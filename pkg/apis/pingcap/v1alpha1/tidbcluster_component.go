@@ -32,10 +32,13 @@ type ComponentAccessor interface {
 	PriorityClassName() *string
 	NodeSelector() map[string]string
 	Annotations() map[string]string
+	Labels() map[string]string
 	Tolerations() []corev1.Toleration
+	TopologySpreadConstraints() []TopologySpreadConstraint
 	PodSecurityContext() *corev1.PodSecurityContext
 	SchedulerName() string
 	DnsPolicy() corev1.DNSPolicy
+	DNSConfig() *corev1.PodDNSConfig
 	ConfigUpdateStrategy() ConfigUpdateStrategy
 	BuildPodSpec() corev1.PodSpec
 	Env() []corev1.EnvVar
@@ -57,6 +60,7 @@ type componentAccessorImpl struct {
 	clusterNodeSelector       map[string]string
 	clusterAnnotations        map[string]string
 	tolerations               []corev1.Toleration
+	topologySpreadConstraints []TopologySpreadConstraint
 	configUpdateStrategy      ConfigUpdateStrategy
 	statefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType
 
@@ -155,6 +159,14 @@ func (a *componentAccessorImpl) Annotations() map[string]string {
 	return anno
 }
 
+func (a *componentAccessorImpl) Labels() map[string]string {
+	l := map[string]string{}
+	for k, v := range a.ComponentSpec.Labels {
+		l[k] = v
+	}
+	return l
+}
+
 func (a *componentAccessorImpl) Tolerations() []corev1.Toleration {
 	tols := a.ComponentSpec.Tolerations
 	if len(tols) == 0 {
@@ -163,7 +175,18 @@ func (a *componentAccessorImpl) Tolerations() []corev1.Toleration {
 	return tols
 }
 
+func (a *componentAccessorImpl) TopologySpreadConstraints() []TopologySpreadConstraint {
+	tscs := a.ComponentSpec.TopologySpreadConstraints
+	if len(tscs) == 0 {
+		tscs = a.topologySpreadConstraints
+	}
+	return tscs
+}
+
 func (a *componentAccessorImpl) DnsPolicy() corev1.DNSPolicy {
+	if a.ComponentSpec.DNSPolicy != "" {
+		return a.ComponentSpec.DNSPolicy
+	}
 	dnsPolicy := corev1.DNSClusterFirst // same as kubernetes default
 	if a.HostNetwork() {
 		dnsPolicy = corev1.DNSClusterFirstWithHostNet
@@ -171,6 +194,10 @@ func (a *componentAccessorImpl) DnsPolicy() corev1.DNSPolicy {
 	return dnsPolicy
 }
 
+func (a *componentAccessorImpl) DNSConfig() *corev1.PodDNSConfig {
+	return a.ComponentSpec.DNSConfig
+}
+
 func (a *componentAccessorImpl) ConfigUpdateStrategy() ConfigUpdateStrategy {
 	strategy := a.ComponentSpec.ConfigUpdateStrategy
 	if strategy == nil {
@@ -193,6 +220,11 @@ func (a *componentAccessorImpl) BuildPodSpec() corev1.PodSpec {
 		RestartPolicy:   corev1.RestartPolicyAlways,
 		Tolerations:     a.Tolerations(),
 		SecurityContext: a.PodSecurityContext(),
+		DNSConfig:       a.DNSConfig(),
+	}
+	// keep the dnsPolicy empty if it's not specified, so the existing StatefulSets are not updated
+	if a.ComponentSpec.DNSPolicy != "" {
+		spec.DNSPolicy = a.ComponentSpec.DNSPolicy
 	}
 	if a.PriorityClassName() != nil {
 		spec.PriorityClassName = *a.PriorityClassName()
@@ -241,6 +273,7 @@ func buildTidbClusterComponentAccessor(spec *TidbClusterSpec, componentSpec *Com
 		clusterNodeSelector:       spec.NodeSelector,
 		clusterAnnotations:        spec.Annotations,
 		tolerations:               spec.Tolerations,
+		topologySpreadConstraints: spec.TopologySpreadConstraints,
 		configUpdateStrategy:      spec.ConfigUpdateStrategy,
 		statefulSetUpdateStrategy: spec.StatefulSetUpdateStrategy,
 
@@ -250,16 +283,17 @@ func buildTidbClusterComponentAccessor(spec *TidbClusterSpec, componentSpec *Com
 
 func buildDMClusterComponentAccessor(spec *DMClusterSpec, componentSpec *ComponentSpec) ComponentAccessor {
 	return &componentAccessorImpl{
-		imagePullPolicy:      spec.ImagePullPolicy,
-		imagePullSecrets:     spec.ImagePullSecrets,
-		hostNetwork:          spec.HostNetwork,
-		affinity:             spec.Affinity,
-		priorityClassName:    spec.PriorityClassName,
-		schedulerName:        spec.SchedulerName,
-		clusterNodeSelector:  spec.NodeSelector,
-		clusterAnnotations:   spec.Annotations,
-		tolerations:          spec.Tolerations,
-		configUpdateStrategy: ConfigUpdateStrategyRollingUpdate,
+		imagePullPolicy:           spec.ImagePullPolicy,
+		imagePullSecrets:          spec.ImagePullSecrets,
+		hostNetwork:               spec.HostNetwork,
+		affinity:                  spec.Affinity,
+		priorityClassName:         spec.PriorityClassName,
+		schedulerName:             spec.SchedulerName,
+		clusterNodeSelector:       spec.NodeSelector,
+		clusterAnnotations:        spec.Annotations,
+		tolerations:               spec.Tolerations,
+		topologySpreadConstraints: spec.TopologySpreadConstraints,
+		configUpdateStrategy:      ConfigUpdateStrategyRollingUpdate,
 
 		ComponentSpec: componentSpec,
 	}
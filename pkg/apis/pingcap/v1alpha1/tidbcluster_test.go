@@ -320,6 +320,35 @@ func TestComponentAccessor(t *testing.T) {
 				g.Expect(a.SchedulerName()).Should(Equal("override"))
 			},
 		},
+		{
+			name: "dns policy and config",
+			cluster: &TidbClusterSpec{
+				HostNetwork: pointer.BoolPtr(true),
+			},
+			component: &ComponentSpec{},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.DnsPolicy()).Should(Equal(corev1.DNSClusterFirstWithHostNet))
+				g.Expect(a.DNSConfig()).Should(BeNil())
+				// the dnsPolicy is not set in the pod spec if it's not specified
+				g.Expect(a.BuildPodSpec().DNSPolicy).Should(BeEmpty())
+			},
+		},
+		{
+			name: "dns policy and config at component-level",
+			cluster: &TidbClusterSpec{
+				HostNetwork: pointer.BoolPtr(true),
+			},
+			component: &ComponentSpec{
+				DNSPolicy: corev1.DNSNone,
+				DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}},
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.DnsPolicy()).Should(Equal(corev1.DNSNone))
+				podSpec := a.BuildPodSpec()
+				g.Expect(podSpec.DNSPolicy).Should(Equal(corev1.DNSNone))
+				g.Expect(podSpec.DNSConfig).Should(Equal(&corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}}))
+			},
+		},
 		{
 			name: "node selector merge",
 			cluster: &TidbClusterSpec{
@@ -380,6 +409,20 @@ func TestComponentAccessor(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name:    "labels at component-level",
+			cluster: &TidbClusterSpec{},
+			component: &ComponentSpec{
+				Labels: map[string]string{
+					"k1": "v1",
+				},
+			},
+			expectFn: func(g *GomegaWithT, a ComponentAccessor) {
+				g.Expect(a.Labels()).Should(Equal(map[string]string{
+					"k1": "v1",
+				}))
+			},
+		},
 		{
 			name: "tolerations merge",
 			cluster: &TidbClusterSpec{
@@ -553,6 +596,17 @@ func TestPDVersion(t *testing.T) {
 				g.Expect(tc.PDVersion()).To(Equal("latest"))
 			},
 		},
+		{
+			name: "registry has port",
+			update: func(tc *TidbCluster) {
+				tc.Spec.PD.Image = "pingcap/pd"
+				tc.Spec.ImageRegistry = "localhost:5000"
+			},
+			expectFn: func(g *GomegaWithT, tc *TidbCluster) {
+				g.Expect(tc.PDImage()).To(Equal("localhost:5000/pingcap/pd"))
+				g.Expect(tc.PDVersion()).To(Equal("latest"))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -560,6 +614,26 @@ func TestPDVersion(t *testing.T) {
 	}
 }
 
+func TestImageRegistry(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Spec.Version = "v5.0.0"
+	tc.Spec.PD.BaseImage = "pingcap/pd"
+	tc.Spec.TiKV.BaseImage = "registry.example.com/pingcap/tikv"
+	tc.Spec.TiDB.BaseImage = "localhost/pingcap/tidb"
+	g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v5.0.0"))
+
+	tc.Spec.ImageRegistry = "mirror.example.com/"
+	g.Expect(tc.PDImage()).To(Equal("mirror.example.com/pingcap/pd:v5.0.0"))
+	g.Expect(tc.PDVersion()).To(Equal("v5.0.0"))
+	// the images which specify a registry are not changed
+	g.Expect(tc.TiKVImage()).To(Equal("registry.example.com/pingcap/tikv:v5.0.0"))
+	g.Expect(tc.TiDBImage()).To(Equal("localhost/pingcap/tidb:v5.0.0"))
+	g.Expect(tc.HelperImage()).To(Equal("mirror.example.com/" + defaultHelperImage))
+	g.Expect(tc.PumpImage()).To(BeNil())
+}
+
 func newTidbCluster() *TidbCluster {
 	return &TidbCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -594,3 +668,67 @@ func newTidbCluster() *TidbCluster {
 		},
 	}
 }
+
+func TestTiKVStoresWarmedUp(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name     string
+		percent  *int32
+		stores   map[string]TiKVStore
+		expected bool
+	}{
+		{
+			name:    "percent is not set",
+			percent: nil,
+			stores: map[string]TiKVStore{
+				"1": {ID: "1", State: TiKVStateUp, RegionCount: 100},
+				"2": {ID: "2", State: TiKVStateUp, RegionCount: 0},
+			},
+			expected: true,
+		},
+		{
+			name:     "no stores",
+			percent:  pointer.Int32Ptr(50),
+			stores:   map[string]TiKVStore{},
+			expected: true,
+		},
+		{
+			name:    "new store is not balanced",
+			percent: pointer.Int32Ptr(50),
+			stores: map[string]TiKVStore{
+				"1": {ID: "1", State: TiKVStateUp, RegionCount: 100},
+				"2": {ID: "2", State: TiKVStateUp, RegionCount: 100},
+				"3": {ID: "3", State: TiKVStateUp, RegionCount: 10},
+			},
+			expected: false,
+		},
+		{
+			name:    "new store is balanced",
+			percent: pointer.Int32Ptr(50),
+			stores: map[string]TiKVStore{
+				"1": {ID: "1", State: TiKVStateUp, RegionCount: 100},
+				"2": {ID: "2", State: TiKVStateUp, RegionCount: 100},
+				"3": {ID: "3", State: TiKVStateUp, RegionCount: 40},
+			},
+			expected: true,
+		},
+		{
+			name:    "ignore stores not up",
+			percent: pointer.Int32Ptr(50),
+			stores: map[string]TiKVStore{
+				"1": {ID: "1", State: TiKVStateUp, RegionCount: 100},
+				"2": {ID: "2", State: TiKVStateUp, RegionCount: 100},
+				"3": {ID: "3", State: TiKVStateDown, RegionCount: 0},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		tc := newTidbCluster()
+		tc.Spec.TiKV.WarmUpRegionPercent = tt.percent
+		tc.Status.TiKV.Stores = tt.stores
+		g.Expect(tc.TiKVStoresWarmedUp()).To(Equal(tt.expected))
+	}
+}
@@ -19,6 +19,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -72,6 +73,23 @@ const (
 	UnknownMemberType MemberType = "unknown"
 )
 
+const (
+	// DefaultPDClientPort is the port of the client URLs served by PD
+	DefaultPDClientPort = 2379
+	// DefaultTiKVServerPort is the port of the gRPC server of TiKV
+	DefaultTiKVServerPort = 20160
+	// DefaultTiFlashProxyPort is the port of the proxy served by TiFlash
+	DefaultTiFlashProxyPort = 3930
+	// DefaultTiCDCPort is the port served by TiCDC
+	DefaultTiCDCPort = 8301
+	// DefaultPumpPort is the port served by Pump
+	DefaultPumpPort = 8250
+	// DefaultDMMasterPort is the port served by DM-master
+	DefaultDMMasterPort = 8261
+	// DefaultDMWorkerPort is the port served by DM-worker
+	DefaultDMWorkerPort = 8262
+)
+
 // MemberPhase is the current state of member
 type MemberPhase string
 
@@ -188,6 +206,11 @@ type TidbClusterSpec struct {
 	// +optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
+	// ImageRegistry is the registry, e.g. a mirror in an air-gapped environment, prefixed to the images
+	// of TiDB cluster Pods which do not specify a registry.
+	// +optional
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+
 	// ConfigUpdateStrategy determines how the configuration change is applied to the cluster.
 	// UpdateStrategyInPlace will update the ConfigMap of configuration in-place and an extra rolling-update of the
 	// cluster component is needed to reload the configuration change.
@@ -234,6 +257,11 @@ type TidbClusterSpec struct {
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// TopologySpreadConstraints describes how a group of pods ought to spread across topology
+	// domains, components may override it respectively.
+	// +optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
 	// Time zone of TiDB cluster Pods
 	// Optional: Defaults to UTC
 	// +optional
@@ -265,6 +293,17 @@ type TidbClusterSpec struct {
 	// StatefulSetUpdateStrategy of TiDB cluster StatefulSets
 	// +optional
 	StatefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType `json:"statefulSetUpdateStrategy,omitempty"`
+
+	// EnablePodDisruptionBudget indicates whether to create PodDisruptionBudgets for PD and TiKV,
+	// PD keeps the quorum and at most one TiKV is disrupted at a time.
+	// Optional: Defaults to false
+	// +optional
+	EnablePodDisruptionBudget *bool `json:"enablePodDisruptionBudget,omitempty"`
+
+	// FeatureGates overrides the feature gates of the operator for this cluster, only the cluster scoped
+	// features can be set here, e.g. StableScheduling.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
 
 // TidbClusterStatus represents the current status of a tidb cluster.
@@ -281,8 +320,42 @@ type TidbClusterStatus struct {
 	// Represents the latest available observations of a tidb cluster's state.
 	// +optional
 	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// LastReconcile records the result of the last reconciliation of the tidb cluster.
+	// +optional
+	LastReconcile *ReconcileStatus `json:"lastReconcile,omitempty"`
+}
+
+// ReconcileStatus describes the result of a reconciliation and the task it stopped at.
+type ReconcileStatus struct {
+	// Task is the name of the task which failed or is waiting, empty if the reconciliation succeeded.
+	// +optional
+	Task string `json:"task,omitempty"`
+	// Result of the reconciliation, one of Succeeded, Requeued, Failed.
+	Result ReconcileResult `json:"result"`
+	// Reason is a short CamelCase reason of what the task is waiting for, e.g. WaitingForLeaderEviction,
+	// it's only set by some tasks when the reconciliation is requeued.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating why the reconciliation did not succeed.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Last time the task, result or message of the reconciliation changed.
+	Time metav1.Time `json:"time,omitempty"`
 }
 
+// ReconcileResult represents the result of a reconciliation.
+type ReconcileResult string
+
+const (
+	// ReconcileSucceeded means all tasks of the reconciliation succeeded.
+	ReconcileSucceeded ReconcileResult = "Succeeded"
+	// ReconcileRequeued means a task is waiting for something to finish and the
+	// reconciliation is requeued.
+	ReconcileRequeued ReconcileResult = "Requeued"
+	// ReconcileFailed means a task of the reconciliation returned an error.
+	ReconcileFailed ReconcileResult = "Failed"
+)
+
 // TidbClusterCondition describes the state of a tidb cluster at a certain point.
 type TidbClusterCondition struct {
 	// Type of the condition.
@@ -300,6 +373,10 @@ type TidbClusterCondition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the .metadata.generation of the tidb cluster the condition
+	// was set based upon, the condition is out of date if it's less than the generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // TidbClusterConditionType represents a tidb cluster condition value.
@@ -314,6 +391,26 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TidbClusterReady TidbClusterConditionType = "Ready"
+	// TidbClusterTiKVSlowStore indicates whether any TiKV store is reported
+	// as slow by PD, it's only set when TiKV slow store protection is enabled.
+	TidbClusterTiKVSlowStore TidbClusterConditionType = "TiKVSlowStore"
+	// TidbClusterTiKVDiskUsageHigh indicates whether the disk usage of any TiKV
+	// store exceeds the threshold, it's only set when the threshold is configured.
+	TidbClusterTiKVDiskUsageHigh TidbClusterConditionType = "TiKVDiskUsageHigh"
+	// TidbClusterSQLReady indicates whether all TiDB instances can execute SQL,
+	// it's only set when spec.tidb.sqlHealthCheckSecret is configured.
+	TidbClusterSQLReady TidbClusterConditionType = "SQLReady"
+	// TidbClusterTiKVWarmedUp indicates whether the TiKV stores are balanced after
+	// scaling, it's only set when spec.tikv.warmUpRegionPercent is configured.
+	TidbClusterTiKVWarmedUp TidbClusterConditionType = "TiKVWarmedUp"
+	// TidbClusterProgressing indicates whether any component is being upgraded or
+	// scaled, or any statefulset is not up to date.
+	TidbClusterProgressing TidbClusterConditionType = "Progressing"
+	// TidbClusterSuspended indicates whether the tidb cluster is paused by spec.paused.
+	TidbClusterSuspended TidbClusterConditionType = "Suspended"
+	// TidbClusterDegraded indicates whether any PD, TiKV, TiDB or TiFlash member
+	// has failed and is being failed over.
+	TidbClusterDegraded TidbClusterConditionType = "Degraded"
 )
 
 // +k8s:openapi-gen=true
@@ -331,6 +428,12 @@ type PDSpec struct {
 	// Specify a Service Account for pd
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 
+	// PodSpecOverlay is a strategic merge patch applied to the generated Pod spec, it can be used
+	// to set the fields which are not exposed in the spec, e.g. volumes, env or tolerations.
+	// Changing it triggers a rolling update.
+	// +optional
+	PodSpecOverlay *runtime.RawExtension `json:"podSpecOverlay,omitempty"`
+
 	// The desired ready replicas
 	// +kubebuilder:validation:Minimum=1
 	Replicas int32 `json:"replicas"`
@@ -399,6 +502,12 @@ type TiKVSpec struct {
 	// Specify a Service Account for tikv
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 
+	// PodSpecOverlay is a strategic merge patch applied to the generated Pod spec, it can be used
+	// to set the fields which are not exposed in the spec, e.g. volumes, env or tolerations.
+	// Changing it triggers a rolling update.
+	// +optional
+	PodSpecOverlay *runtime.RawExtension `json:"podSpecOverlay,omitempty"`
+
 	// The desired ready replicas
 	// +kubebuilder:validation:Minimum=1
 	Replicas int32 `json:"replicas"`
@@ -467,6 +576,81 @@ type TiKVSpec struct {
 	// +optional
 	EvictLeaderTimeout *string `json:"evictLeaderTimeout,omitempty"`
 
+	// UpdatePartition indicates that only the TiKV Pods with an ordinal that is greater than
+	// or equal to the partition will be updated when the TiKV StatefulSet is rolling updated,
+	// which allows to try a new version on a subset of TiKV Pods before a full rollout.
+	// TiKV stays in Upgrade phase until the partition is lowered to 0 or removed.
+	// Optional: Defaults to 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	UpdatePartition *int32 `json:"updatePartition,omitempty"`
+
+	// Canary updates the canary TiKV Pods first when the TiKV StatefulSet is rolling updated,
+	// then the rollout pauses until it is promoted, either automatically after canary.autoPromoteAfter
+	// or manually by annotating the TidbCluster with `tikv.tidb.pingcap.com/canary-promote` whose
+	// value is the update revision in status.tikv.statefulSet.updateRevision.
+	// If UpdatePartition is set as well, the larger partition takes effect.
+	// +optional
+	Canary *CanarySpec `json:"canary,omitempty"`
+
+	// UpgradeHooks runs the hook containers as Jobs before and after the TiKV StatefulSet
+	// is rolling updated to a new revision. Only TiKV supports upgrade hooks for now.
+	// +optional
+	UpgradeHooks *UpgradeHooks `json:"upgradeHooks,omitempty"`
+
+	// ScaleInPolicy decides which TiKV Pod is removed when TiKV is scaled in.
+	// Policies other than HighestOrdinal require the AdvancedStatefulSet feature, the ordinals
+	// of the removed Pods are recorded in the `tikv.tidb.pingcap.com/delete-slots` annotation
+	// of the TidbCluster so that they are not scaled out again, and the TiKV Pods annotated with
+	// `tidb.pingcap.com/scale-in-priority` are removed first in descending order of the priority.
+	// NewestPod and OldestPod compare the order in which the TiKV stores are created, a Pod
+	// without a store is regarded as the newest one.
+	// If topologySpreadConstraints are set, the Pod is chosen among the Pods in the most
+	// populated topology domain to keep the TiKV Pods spread evenly.
+	// Optional: Defaults to HighestOrdinal
+	// +kubebuilder:validation:Enum=HighestOrdinal,FewestRegions,NewestPod,OldestPod
+	// +optional
+	ScaleInPolicy ScaleInPolicy `json:"scaleInPolicy,omitempty"`
+
+	// WarmUpRegionPercent makes the TiKVWarmedUp condition stay False after scaling until
+	// the region count of every Up store reaches the percent of the average region count
+	// of the Up stores, so that users can tell when the added stores are balanced.
+	// The condition becomes True anyway after 30 minutes.
+	// Optional: Defaults to nil, which means not tracking whether the stores are balanced
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	WarmUpRegionPercent *int32 `json:"warmUpRegionPercent,omitempty"`
+
+	// SlowStoreProtection indicates whether to evict the region leaders of a TiKV store
+	// when PD reports it as slow for 5 minutes, the evict leader scheduler is removed once
+	// the store recovers. The leaders of at most one slow store are evicted at a time, and
+	// none are evicted while any other store has an evict leader scheduler.
+	// It requires PD to report the slow score of stores.
+	// Optional: Defaults to false
+	// +optional
+	SlowStoreProtection *bool `json:"slowStoreProtection,omitempty"`
+
+	// UpgradeDiskUsageThreshold is the disk usage percent of TiKV stores above which
+	// the rolling update of TiKV is blocked, since restarting a TiKV Pod in that state
+	// may fill up the disks of the other stores while the regions are rebalanced.
+	// Optional: Defaults to nil, which means not checking the disk usage
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	UpgradeDiskUsageThreshold *int32 `json:"upgradeDiskUsageThreshold,omitempty"`
+
+	// StoreWeight sets the leader and region weight of the TiKV stores in PD, so that
+	// PD balances more or less leaders and regions to the stores of this TidbCluster,
+	// e.g. for the stores with larger disks in a heterogeneous cluster.
+	// The weights set by pd-ctl are kept if it is not set.
+	// +optional
+	StoreWeight *StoreWeight `json:"storeWeight,omitempty"`
+
+	// CPUPinning pins the TiKV processes to dedicated CPUs by the static CPU manager policy of kubelet.
+	// +optional
+	CPUPinning *CPUPinning `json:"cpuPinning,omitempty"`
+
 	// StorageVolumes configure additional storage for TiKV pods.
 	// +optional
 	StorageVolumes []StorageVolume `json:"storageVolumes,omitempty"`
@@ -480,6 +664,94 @@ type TiKVSpec struct {
 	EnableNamedStatusPort bool `json:"enableNamedStatusPort,omitempty"`
 }
 
+// ScaleInPolicy represents how the Pod to be removed is chosen on scale-in
+type ScaleInPolicy string
+
+const (
+	// ScaleInPolicyHighestOrdinal removes the Pod with the highest ordinal
+	ScaleInPolicyHighestOrdinal ScaleInPolicy = "HighestOrdinal"
+	// ScaleInPolicyFewestRegions removes the TiKV Pod whose store has the fewest regions,
+	// so that the least data is migrated
+	ScaleInPolicyFewestRegions ScaleInPolicy = "FewestRegions"
+	// ScaleInPolicyNewestPod removes the TiKV Pod whose store is created most recently,
+	// i.e. the store with the largest ID
+	ScaleInPolicyNewestPod ScaleInPolicy = "NewestPod"
+	// ScaleInPolicyOldestPod removes the TiKV Pod whose store is created earliest,
+	// i.e. the store with the smallest ID
+	ScaleInPolicyOldestPod ScaleInPolicy = "OldestPod"
+)
+
+// CanarySpec describes the canary rollout of a component
+// +k8s:openapi-gen=true
+type CanarySpec struct {
+	// Replicas is the number of Pods with the largest ordinals which are updated first
+	// when the StatefulSet is rolling updated.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+
+	// AutoPromoteAfter promotes the rollout automatically when the canary Pods have been
+	// updated and healthy for the duration, in the format of Go Duration.
+	// Optional: Defaults to nil, which means the rollout is only promoted manually
+	// +optional
+	AutoPromoteAfter *string `json:"autoPromoteAfter,omitempty"`
+}
+
+// UpgradeHooks describes the Jobs run around the rolling update of TiKV, the other
+// components do not support upgrade hooks yet. A hook Job is created once for each update revision of the StatefulSet, the
+// revision is passed to the hook container by the UPDATE_REVISION env. A failed
+// hook Job blocks the upgrade until it is deleted, then the operator creates it again.
+// Finished hook Jobs are deleted after 24 hours.
+// +k8s:openapi-gen=true
+type UpgradeHooks struct {
+	// PreUpgrade is the container run before the first Pod is updated to the new revision,
+	// the Pods are not updated until the Job completes.
+	// +optional
+	PreUpgrade *corev1.Container `json:"preUpgrade,omitempty"`
+
+	// PostUpgrade is the container run after all Pods are updated to the new revision,
+	// the component stays in Upgrade phase until the Job completes.
+	// +optional
+	PostUpgrade *corev1.Container `json:"postUpgrade,omitempty"`
+}
+
+// StoreWeight is the weight of TiKV stores used by PD to balance leaders and regions
+// +k8s:openapi-gen=true
+type StoreWeight struct {
+	// Leader is the leader weight of the stores, it must be a positive number, e.g. "1.5"
+	// Optional: Defaults to "1"
+	// +optional
+	Leader *string `json:"leader,omitempty"`
+
+	// Region is the region weight of the stores, it must be a positive number, e.g. "1.5"
+	// Optional: Defaults to "1"
+	// +optional
+	Region *string `json:"region,omitempty"`
+}
+
+// CPUPinning describes how the processes of a component are pinned to dedicated CPUs
+// by the static CPU manager policy of kubelet
+// +k8s:openapi-gen=true
+type CPUPinning struct {
+	// Enabled shapes the resources of the Pods to be in the Guaranteed QoS class, the limits
+	// of cpu and memory default to the requests and vice versa, so that kubelet with the static
+	// CPU manager policy allocates exclusive CPUs to the main container.
+	// The cpu of the main container must be an integer and the resources of the sidecars,
+	// initContainers and additionalContainers must be set, otherwise the Pods are not created.
+	// Optional: Defaults to false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// NUMANode is the NUMA node which the processes are bound to, they are started by
+	// `numactl --cpunodebind=<NUMANode> --membind=<NUMANode>`, so numactl must be installed
+	// in the image. It is a hint that should be consistent with the CPUs allocated by kubelet,
+	// e.g. with the single-numa-node topology manager policy on nodes with the same topology.
+	// It takes effect only if Enabled is true.
+	// Optional: Defaults to nil, which means the processes are not bound to any NUMA node
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NUMANode *int32 `json:"numaNode,omitempty"`
+}
+
 // TiFlashSpec contains details of TiFlash members
 // +k8s:openapi-gen=true
 type TiFlashSpec struct {
@@ -493,6 +765,13 @@ type TiFlashSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	Replicas int32 `json:"replicas"`
 
+	// Suspend stops all the Pods of TiFlash by scaling its StatefulSet to 0 directly, the TiFlash stores
+	// are not removed from the cluster and the persistent volumes are retained, so that TiFlash
+	// can be resumed by setting it back to false.
+	// Optional: Defaults to false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// Base image of the component, image tag is now allowed during validation
 	// +kubebuilder:default=pingcap/tiflash
 	// +optional
@@ -525,6 +804,10 @@ type TiFlashSpec struct {
 	// RecoverFailover indicates that Operator can recover the failover Pods
 	// +optional
 	RecoverFailover bool `json:"recoverFailover,omitempty"`
+
+	// ConfigReloader is the configurations of the sidecar which reloads the TiFlash config
+	// +optional
+	ConfigReloader *TiFlashConfigReloaderSpec `json:"configReloader,omitempty"`
 }
 
 // TiCDCSpec contains details of TiCDC members
@@ -540,6 +823,13 @@ type TiCDCSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	Replicas int32 `json:"replicas"`
 
+	// Suspend stops all the Pods of TiCDC by scaling its StatefulSet to 0 directly, the TiCDC captures
+	// are not removed from the cluster and the persistent volumes are retained, so that TiCDC
+	// can be resumed by setting it back to false.
+	// Optional: Defaults to false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// Base image of the component, image tag is now allowed during validation
 	// +kubebuilder:default=pingcap/ticdc
 	// +optional
@@ -548,6 +838,12 @@ type TiCDCSpec struct {
 	// Config is the Configuration of tidbcdc servers
 	// +optional
 	Config *TiCDCConfig `json:"config,omitempty"`
+
+	// GracefulShutdownTimeout is the timeout of draining the capture before the TiCDC Pod
+	// is restarted or removed, in the format of Go Duration.
+	// Defaults to 10m
+	// +optional
+	GracefulShutdownTimeout *string `json:"gracefulShutdownTimeout,omitempty"`
 }
 
 // TiCDCConfig is the configuration of tidbcdc
@@ -580,6 +876,19 @@ type LogTailerSpec struct {
 	corev1.ResourceRequirements `json:",inline"`
 }
 
+// TiFlashConfigReloaderSpec represents an optional sidecar container which reloads the TiFlash config
+// +k8s:openapi-gen=true
+type TiFlashConfigReloaderSpec struct {
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Whether to update the profiles, users and quotas of the TiFlash config in place and
+	// reload them without a rolling update. It only takes effect with the RollingUpdate config
+	// update strategy, enabling or disabling it results in a rolling update.
+	// Optional: Defaults to false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
 // StorageClaim contains details of TiFlash storages
 // +k8s:openapi-gen=true
 type StorageClaim struct {
@@ -602,10 +911,23 @@ type TiDBSpec struct {
 	// Specify a Service Account for tidb
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 
+	// PodSpecOverlay is a strategic merge patch applied to the generated Pod spec, it can be used
+	// to set the fields which are not exposed in the spec, e.g. volumes, env or tolerations.
+	// Changing it triggers a rolling update.
+	// +optional
+	PodSpecOverlay *runtime.RawExtension `json:"podSpecOverlay,omitempty"`
+
 	// The desired ready replicas
 	// +kubebuilder:validation:Minimum=0
 	Replicas int32 `json:"replicas"`
 
+	// Suspend stops all the Pods of TiDB by scaling its StatefulSet to 0 directly, the TiDB members
+	// are not removed from the cluster and the persistent volumes are retained, so that TiDB
+	// can be resumed by setting it back to false.
+	// Optional: Defaults to false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// Base image of the component, image tag is now allowed during validation
 	// +kubebuilder:default=pingcap/tidb
 	// +optional
@@ -645,6 +967,34 @@ type TiDBSpec struct {
 	// +optional
 	TLSClient *TiDBTLSClient `json:"tlsClient,omitempty"`
 
+	// AuthTokenJWKSSecret is the name of the secret that contains the JSON Web Key Set
+	// under the key `jwks.json` for the `tidb_auth_token` authentication method.
+	// The secret is mounted into TiDB Pods and `security.auth-token-jwks` is set to
+	// it, TiDB reloads the updated key set periodically when the secret is rotated.
+	// Optional: Defaults to nil
+	// +optional
+	AuthTokenJWKSSecret *string `json:"authTokenJWKSSecret,omitempty"`
+
+	// SQLHealthCheckSecret is the name of the secret that contains the `user` and
+	// `password` keys, which are used by the operator to execute `SELECT 1` against
+	// each TiDB instance and maintain the `SQLReady` condition of the cluster.
+	// Optional: Defaults to nil
+	// +optional
+	SQLHealthCheckSecret *string `json:"sqlHealthCheckSecret,omitempty"`
+
+	// BootstrapSQLSecret is the name of the secret that contains the SQL statements
+	// under the key `bootstrap-sql`. The secret is mounted into TiDB Pods and
+	// `initialize-sql-file` is set to it, TiDB executes the statements only once
+	// when the cluster is bootstrapped. It can be unset but can not be changed to another
+	// secret after the cluster is created. Requires TiDB v6.5.0 or later.
+	// Optional: Defaults to nil
+	// +optional
+	BootstrapSQLSecret *string `json:"bootstrapSQLSecret,omitempty"`
+
+	// CPUPinning pins the TiDB processes to dedicated CPUs by the static CPU manager policy of kubelet.
+	// +optional
+	CPUPinning *CPUPinning `json:"cpuPinning,omitempty"`
+
 	// Plugins is a list of plugins that are loaded by TiDB server, empty means plugin disabled
 	// +optional
 	Plugins []string `json:"plugins,omitempty"`
@@ -669,10 +1019,6 @@ type TiDBSpec struct {
 	// Defaults to Kubernetes default storage class.
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
-	// ReadinessProbe describes actions that probe the tidb's readiness.
-	// the default behavior is like setting type as "tcp"
-	// +optional
-	ReadinessProbe *TiDBProbe `json:"readinessProbe,omitempty"`
 }
 
 const (
@@ -680,20 +1026,53 @@ const (
 	TCPProbeType string = "tcp"
 	// CommandProbeType represents the readiness prob method with arbitrary unix `exec` call format commands
 	CommandProbeType string = "command"
+	// HTTPProbeType represents the readiness prob method with HTTP GET request
+	HTTPProbeType string = "http"
 )
 
-// TiDBProbe contains details of probing tidb.
+// Probe contains details of probing the component.
 // +k8s:openapi-gen=true
-// default probe by TCPPort on 4000.
-type TiDBProbe struct {
-	// "tcp" will use TCP socket to connetct port 4000
+// default probe by TCPPort of the component.
+type Probe struct {
+	// "tcp" will use TCP socket to connect the component port.
 	//
 	// "command" will probe the status api of tidb.
 	// This will use curl command to request tidb, before v4.0.9 there is no curl in the image,
 	// So do not use this before v4.0.9.
-	// +kubebuilder:validation:Enum=tcp,command
+	//
+	// "http" will probe the status api of tidb by HTTP GET request from kubelet,
+	// it falls back to "command" if TLS is enabled between cluster components,
+	// because kubelet can not present the client certificate.
+	//
+	// "command" and "http" are only supported by tidb.
+	// +kubebuilder:validation:Enum=tcp,command,http
 	// +optional
 	Type *string `json:"type,omitempty"` // tcp or command
+
+	// Number of seconds after the container has started before probes are initiated.
+	// Defaults to 10 seconds.
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// How often (in seconds) to perform the probe.
+	// Defaults to 10 seconds. Minimum value is 1.
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// Number of seconds after which the probe times out.
+	// Defaults to 1 second. Minimum value is 1.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// Defaults to 1. Minimum value is 1.
+	// +optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// Defaults to 3. Minimum value is 1.
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
 }
 
 // PumpSpec contains details of Pump members
@@ -723,6 +1102,12 @@ type PumpSpec struct {
 	// +optional
 	Config *config.GenericConfig `json:"config,omitempty"`
 
+	// OfflineTimeout is the timeout of making the pump node offline before the Pump Pod
+	// is removed on scale-in, in the format of Go Duration.
+	// Defaults to 10m
+	// +optional
+	OfflineTimeout *string `json:"offlineTimeout,omitempty"`
+
 	// +k8s:openapi-gen=false
 	// For backward compatibility with helm chart
 	SetTimeZone *bool `json:"setTimeZone,omitempty"`
@@ -785,6 +1170,15 @@ type ComponentSpec struct {
 	// +optional
 	HostNetwork *bool `json:"hostNetwork,omitempty"`
 
+	// DNSPolicy of the component Pods.
+	// Optional: Defaults to ClusterFirstWithHostNet if hostNetwork is enabled, otherwise ClusterFirst
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig of the component Pods, it's merged into the DNS configuration generated by dnsPolicy.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
 	// Affinity of the component. Override the cluster-level setting if present.
 	// Optional: Defaults to cluster-level setting
 	// +optional
@@ -810,11 +1204,21 @@ type ComponentSpec struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 
+	// Labels of the component Pods. The labels managed by TiDB Operator can not be overridden
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Tolerations of the component. Override the cluster-level tolerations if non-empty
 	// Optional: Defaults to cluster-level setting
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// TopologySpreadConstraints of the component. Override the cluster-level
+	// topologySpreadConstraints if non-empty
+	// Optional: Defaults to cluster-level setting
+	// +optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
 	// PodSecurityContext of the component
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
@@ -870,6 +1274,23 @@ type ComponentSpec struct {
 	// Template.
 	// +optional
 	StatefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType `json:"statefulSetUpdateStrategy,omitempty"`
+
+	// ReadinessProbe describes actions that probe the readiness of the component.
+	// The default behavior of tidb is like setting type as "tcp",
+	// other components are not probed if not specified.
+	// +optional
+	ReadinessProbe *Probe `json:"readinessProbe,omitempty"`
+}
+
+// TopologySpreadConstraint specifies how to spread the Pods of a component among the given
+// topology. The Pods are spread evenly with MaxSkew 1 and DoNotSchedule, and the label
+// selector is generated from the component labels.
+// +k8s:openapi-gen=true
+type TopologySpreadConstraint struct {
+	// TopologyKey is the key of node labels. Nodes that have a label with this key
+	// and identical values are considered to be in the same topology, e.g.
+	// topology.kubernetes.io/zone.
+	TopologyKey string `json:"topologyKey"`
 }
 
 // ServiceSpec specifies the service object in k8s
@@ -997,6 +1418,9 @@ type TiDBStatus struct {
 	FailureMembers           map[string]TiDBFailureMember `json:"failureMembers,omitempty"`
 	ResignDDLOwnerRetryCount int32                        `json:"resignDDLOwnerRetryCount,omitempty"`
 	Image                    string                       `json:"image,omitempty"`
+	// Hash of the certificate in the TiDB server Secret which has been reloaded by all TiDB servers,
+	// it's only recorded when spec.tidb.tlsClient.rotationPolicy is Reload.
+	TLSServerCertHash string `json:"tlsServerCertHash,omitempty"`
 }
 
 // TiDBMember is TiDB member
@@ -1007,6 +1431,13 @@ type TiDBMember struct {
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 	// Node hosting pod of this TiDB member.
 	NodeName string `json:"node,omitempty"`
+	// Whether this TiDB member is the DDL owner.
+	DDLOwner bool `json:"ddlOwner,omitempty"`
+	// Zone label of this TiDB member, resolved from the topology labels of the node.
+	Zone string `json:"zone,omitempty"`
+	// Whether this TiDB member can execute SQL, it's only checked when
+	// spec.tidb.sqlHealthCheckSecret is configured.
+	SQLReady bool `json:"sqlReady,omitempty"`
 }
 
 // TiDBFailureMember is the tidb failure member information
@@ -1026,6 +1457,21 @@ type TiKVStatus struct {
 	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
 	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
 	Image           string                      `json:"image,omitempty"`
+	// The revision which the canary TiKV Pods have been updated to, it's only recorded
+	// when spec.tikv.canary is set.
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+	// The time when the canary TiKV Pods have been updated to CanaryRevision.
+	CanaryUpdatedAt metav1.Time `json:"canaryUpdatedAt,omitempty"`
+	// The update revision whose pre-upgrade hook Job has completed, it's only recorded
+	// when spec.tikv.upgradeHooks.preUpgrade is set.
+	PreUpgradeHookRevision string `json:"preUpgradeHookRevision,omitempty"`
+	// The update revision whose post-upgrade hook Job has completed, it's only recorded
+	// when spec.tikv.upgradeHooks.postUpgrade is set.
+	PostUpgradeHookRevision string `json:"postUpgradeHookRevision,omitempty"`
+	// The stores whose evict leader schedulers are added by TiDB Operator, keyed by store ID,
+	// with the time when the schedulers are added. The evict leader schedulers of the other
+	// stores, e.g. added by pd-ctl, are not removed by TiDB Operator.
+	EvictLeaderStores map[string]metav1.Time `json:"evictLeaderStores,omitempty"`
 }
 
 // TiFlashStatus is TiFlash status
@@ -1065,6 +1511,20 @@ type TiKVStore struct {
 	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
 	// Last time the health transitioned from one to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// RegionCount is the number of regions on the store reported by PD,
+	// it shows the data migration progress when the store is going offline.
+	RegionCount int32 `json:"regionCount,omitempty"`
+	// EvictLeaderScheduler is the name of the evict leader scheduler of the store
+	// if it exists in PD.
+	EvictLeaderScheduler string `json:"evictLeaderScheduler,omitempty"`
+	// SlowScore is the slow score of the store reported by PD, from 1 to 100,
+	// a higher score means the store is slower.
+	SlowScore int32 `json:"slowScore,omitempty"`
+	// SlowSince is the time when the store is first found slow, it's unset when
+	// the store is not slow.
+	SlowSince *metav1.Time `json:"slowSince,omitempty"`
+	// DiskUsagePercent is the used percent of the store capacity reported by PD.
+	DiskUsagePercent int32 `json:"diskUsagePercent,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -1098,8 +1558,28 @@ type TiDBTLSClient struct {
 	//   4. Set Enabled to `true`.
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+
+	// RotationPolicy determines how the rotated certificate in the Secret <clusterName>-tidb-server-secret
+	// is applied to TiDB servers.
+	// Reload reloads the certificate online by `ALTER INSTANCE RELOAD TLS`, the user in
+	// spec.tidb.sqlHealthCheckSecret is used and it requires the SUPER privilege.
+	// RollingRestart rolling-restarts TiDB servers when the certificate is rotated.
+	// The rotated certificate is not picked up until TiDB servers are restarted if it's not set.
+	// +kubebuilder:validation:Enum=Reload,RollingRestart
+	// +optional
+	RotationPolicy TLSCertRotationPolicy `json:"rotationPolicy,omitempty"`
 }
 
+// TLSCertRotationPolicy represents how the rotated certificate is applied
+type TLSCertRotationPolicy string
+
+const (
+	// TLSCertRotationPolicyReload reloads the rotated certificate online
+	TLSCertRotationPolicyReload TLSCertRotationPolicy = "Reload"
+	// TLSCertRotationPolicyRollingRestart rolling-restarts the pods to use the rotated certificate
+	TLSCertRotationPolicyRollingRestart TLSCertRotationPolicy = "RollingRestart"
+)
+
 // TLSCluster can enable mutual TLS connection between TiDB cluster components
 // https://pingcap.com/docs/stable/how-to/secure/enable-tls-between-components/
 type TLSCluster struct {
@@ -1783,6 +2263,11 @@ type DMClusterSpec struct {
 	// Base tolerations of DM cluster Pods, components may add more tolerations upon this respectively
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints describes how a group of pods ought to spread across topology
+	// domains, components may override it respectively.
+	// +optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 }
 
 // DMClusterStatus represents the current status of a dm cluster.
@@ -2006,5 +2491,11 @@ type StorageVolume struct {
 	Name             string  `json:"name"`
 	StorageClassName *string `json:"storageClassName,omitempty"`
 	StorageSize      string  `json:"storageSize"`
-	MountPath        string  `json:"mountPath"`
+	// MountPath is the path where the volume is mounted in the component container,
+	// it must be an absolute path and must not conflict with the paths used by the component itself.
+	MountPath string `json:"mountPath"`
+	// SubPath is the path within the volume from which the container's volume should be mounted.
+	// Defaults to "" (volume's root).
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
 }
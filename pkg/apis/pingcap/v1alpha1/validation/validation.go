@@ -16,6 +16,7 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"path"
@@ -26,10 +27,13 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilnet "k8s.io/utils/net"
@@ -117,15 +121,28 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.PDAddresses != nil {
 		allErrs = append(allErrs, validatePDAddresses(spec.PDAddresses, fldPath.Child("pdAddresses"))...)
 	}
+	allErrs = append(allErrs, validateFeatureGates(spec.FeatureGates, fldPath.Child("featureGates"))...)
+	return allErrs
+}
+
+// validateFeatureGates makes sure only the cluster scoped features are set in spec.featureGates
+func validateFeatureGates(featureGates map[string]bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for key := range featureGates {
+		if !features.IsClusterScoped(key) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(key), key, features.ClusterScopedFeatures()))
+		}
+	}
 	return allErrs
 }
 
 func validatePDSpec(spec *v1alpha1.PDSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
 	if len(spec.StorageVolumes) > 0 {
-		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
+		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, v1alpha1.PDMemberType, fldPath.Child("storageVolumes"))...)
 	}
 	return allErrs
 }
@@ -148,20 +165,65 @@ func validatePDAddresses(arrayOfAddresses []string, fldPath *field.Path) field.E
 func validateTiKVSpec(spec *v1alpha1.TiKVSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
 	if len(spec.DataSubDir) > 0 {
 		allErrs = append(allErrs, validateLocalDescendingPath(spec.DataSubDir, fldPath.Child("dataSubDir"))...)
 	}
 	if len(spec.StorageVolumes) > 0 {
-		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
+		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, v1alpha1.TiKVMemberType, fldPath.Child("storageVolumes"))...)
 	}
 	allErrs = append(allErrs, validateTimeDurationStr(spec.EvictLeaderTimeout, fldPath.Child("evictLeaderTimeout"))...)
+	if spec.UpdatePartition != nil && (*spec.UpdatePartition < 0 || *spec.UpdatePartition > spec.Replicas) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("updatePartition"), *spec.UpdatePartition,
+			fmt.Sprintf("must be in the range of [0,%d], the replicas of TiKV", spec.Replicas)))
+	}
+	if spec.WarmUpRegionPercent != nil && (*spec.WarmUpRegionPercent <= 0 || *spec.WarmUpRegionPercent >= 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("warmUpRegionPercent"), *spec.WarmUpRegionPercent, "must be in the range of (0,100)"))
+	}
+	if spec.UpgradeDiskUsageThreshold != nil && (*spec.UpgradeDiskUsageThreshold <= 0 || *spec.UpgradeDiskUsageThreshold > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("upgradeDiskUsageThreshold"), *spec.UpgradeDiskUsageThreshold, "must be in the range of (0,100]"))
+	}
+	if spec.StoreWeight != nil {
+		allErrs = append(allErrs, validateStoreWeight(spec.StoreWeight, fldPath.Child("storeWeight"))...)
+	}
+	if spec.Canary != nil {
+		if spec.Canary.Replicas < 0 || spec.Canary.Replicas > spec.Replicas {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("canary", "replicas"), spec.Canary.Replicas,
+				fmt.Sprintf("must be in the range of [0,%d], the replicas of TiKV", spec.Replicas)))
+		}
+		allErrs = append(allErrs, validateTimeDurationStr(spec.Canary.AutoPromoteAfter, fldPath.Child("canary", "autoPromoteAfter"))...)
+	}
+	if spec.CPUPinning != nil {
+		allErrs = append(allErrs, validateNUMANode(spec.CPUPinning.NUMANode, fldPath.Child("cpuPinning", "numaNode"))...)
+	}
+	if spec.IsCPUPinningEnabled() {
+		allErrs = append(allErrs, ValidateGuaranteedResources(spec.ResourceRequirements, true, fldPath)...)
+		if spec.ShouldSeparateRocksDBLog() || spec.ShouldSeparateRaftLog() {
+			allErrs = append(allErrs, ValidateGuaranteedResources(spec.GetLogTailerSpec().ResourceRequirements, false, fldPath.Child("logTailer"))...)
+		}
+		allErrs = append(allErrs, ValidateGuaranteedContainers(spec.InitContainers, fldPath.Child("initContainers"))...)
+		allErrs = append(allErrs, ValidateGuaranteedContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	}
+	return allErrs
+}
+
+// validateStoreWeight validates the leader and region weight are positive numbers
+func validateStoreWeight(weight *v1alpha1.StoreWeight, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, err := weight.GetLeaderWeight(); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leader"), *weight.Leader, "must be a positive number"))
+	}
+	if _, err := weight.GetRegionWeight(); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("region"), *weight.Region, "must be a positive number"))
+	}
 	return allErrs
 }
 
 func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	allErrs = append(allErrs, validateTiFlashConfig(spec.Config, fldPath)...)
 	if len(spec.StorageClaims) < 1 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("spec.StorageClaims"),
@@ -173,6 +235,7 @@ func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.
 func validateTiCDCSpec(spec *v1alpha1.TiCDCSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	return allErrs
 }
 
@@ -230,21 +293,34 @@ func validateTiFlashConfig(config *v1alpha1.TiFlashConfigWraper, path *field.Pat
 func validateTiDBSpec(spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tidbProbeTypes, fldPath.Child("readinessProbe"))...)
 	if spec.Service != nil {
 		allErrs = append(allErrs, validateService(&spec.Service.ServiceSpec, fldPath)...)
 	}
 	if len(spec.StorageVolumes) > 0 {
-		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
+		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, v1alpha1.TiDBMemberType, fldPath.Child("storageVolumes"))...)
 	}
 	if spec.ShouldSeparateSlowLog() && spec.SlowLogVolumeName != "" {
 		allErrs = append(allErrs, validateSlowQueryLogVolume(spec.SlowLogVolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath)...)
 	}
+	if spec.CPUPinning != nil {
+		allErrs = append(allErrs, validateNUMANode(spec.CPUPinning.NUMANode, fldPath.Child("cpuPinning", "numaNode"))...)
+	}
+	if spec.IsCPUPinningEnabled() {
+		allErrs = append(allErrs, ValidateGuaranteedResources(spec.ResourceRequirements, true, fldPath)...)
+		if spec.ShouldSeparateSlowLog() {
+			allErrs = append(allErrs, ValidateGuaranteedResources(spec.GetSlowLogTailerSpec().ResourceRequirements, false, fldPath.Child("slowLogTailer"))...)
+		}
+		allErrs = append(allErrs, ValidateGuaranteedContainers(spec.InitContainers, fldPath.Child("initContainers"))...)
+		allErrs = append(allErrs, ValidateGuaranteedContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	}
 	return allErrs
 }
 
 func validatePumpSpec(spec *v1alpha1.PumpSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	return allErrs
 }
 
@@ -269,6 +345,7 @@ func validateDMClusterSpec(spec *v1alpha1.DMClusterSpec, fldPath *field.Path) fi
 func validateMasterSpec(spec *v1alpha1.MasterSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	// make sure that storageSize for dm-master is assigned
 	if spec.Replicas > 0 && spec.StorageSize == "" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("storageSize"), "storageSize must not be empty"))
@@ -279,6 +356,7 @@ func validateMasterSpec(spec *v1alpha1.MasterSpec, fldPath *field.Path) field.Er
 func validateWorkerSpec(spec *v1alpha1.WorkerSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe, tcpProbeTypes, fldPath.Child("readinessProbe"))...)
 	return allErrs
 }
 
@@ -287,6 +365,105 @@ func validateComponentSpec(spec *v1alpha1.ComponentSpec, fldPath *field.Path) fi
 	// TODO validate other fields
 	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
 	allErrs = append(allErrs, validateAdditionalContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	allErrs = append(allErrs, validateDNS(spec.DNSPolicy, spec.DNSConfig, fldPath)...)
+	allErrs = append(allErrs, validatePodLabels(spec.Labels, fldPath.Child("labels"))...)
+	if spec.TerminationGracePeriodSeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(*spec.TerminationGracePeriodSeconds, fldPath.Child("terminationGracePeriodSeconds"))...)
+	}
+	return allErrs
+}
+
+// tcpProbeTypes are the probe types supported by the components other than tidb
+var tcpProbeTypes = sets.NewString(
+	v1alpha1.TCPProbeType,
+)
+
+// tidbProbeTypes are the probe types supported by tidb, which can probe its status api
+var tidbProbeTypes = sets.NewString(
+	v1alpha1.TCPProbeType,
+	v1alpha1.CommandProbeType,
+	v1alpha1.HTTPProbeType,
+)
+
+// validateProbe validates the probe of a component, the type must be one of supportedProbeTypes,
+// the durations and thresholds must be positive if specified
+func validateProbe(probe *v1alpha1.Probe, supportedProbeTypes sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if probe == nil {
+		return allErrs
+	}
+	if probe.Type != nil && !supportedProbeTypes.Has(*probe.Type) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), *probe.Type, supportedProbeTypes.List()))
+	}
+	if probe.InitialDelaySeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*probe.InitialDelaySeconds), fldPath.Child("initialDelaySeconds"))...)
+	}
+	allErrs = append(allErrs, validatePositiveInt32(probe.PeriodSeconds, fldPath.Child("periodSeconds"))...)
+	allErrs = append(allErrs, validatePositiveInt32(probe.TimeoutSeconds, fldPath.Child("timeoutSeconds"))...)
+	allErrs = append(allErrs, validatePositiveInt32(probe.SuccessThreshold, fldPath.Child("successThreshold"))...)
+	allErrs = append(allErrs, validatePositiveInt32(probe.FailureThreshold, fldPath.Child("failureThreshold"))...)
+	return allErrs
+}
+
+func validatePositiveInt32(v *int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if v != nil && *v < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *v, "must be greater than or equal to 1"))
+	}
+	return allErrs
+}
+
+// managedPodLabelKeys are set on the component Pods by TiDB Operator to select the Pods
+var managedPodLabelKeys = sets.NewString(
+	label.NameLabelKey,
+	label.InstanceLabelKey,
+	label.ComponentLabelKey,
+	label.ManagedByLabelKey,
+)
+
+// validatePodLabels validates the custom labels of the component Pods, the labels managed by TiDB Operator
+// and the ones prefixed by tidb.pingcap.com/ are not allowed
+func validatePodLabels(labels map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, k := range sets.StringKeySet(labels).List() {
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath, k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(labels[k]) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), labels[k], msg))
+		}
+		if managedPodLabelKeys.Has(k) || strings.HasPrefix(k, "tidb.pingcap.com/") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(k), "label is managed by TiDB Operator"))
+		}
+	}
+	return allErrs
+}
+
+// supportedDNSPolicies excludes Default, with which the members can not resolve the addresses of
+// each other as they are advertised with the DNS names of the peer services.
+var supportedDNSPolicies = sets.NewString(
+	string(corev1.DNSClusterFirstWithHostNet),
+	string(corev1.DNSClusterFirst),
+	string(corev1.DNSNone),
+)
+
+// validateDNS validates the dnsPolicy and dnsConfig of a component, the nameservers
+// must be specified in dnsConfig if dnsPolicy is None
+func validateDNS(dnsPolicy corev1.DNSPolicy, dnsConfig *corev1.PodDNSConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if dnsPolicy != "" && !supportedDNSPolicies.Has(string(dnsPolicy)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("dnsPolicy"), dnsPolicy, supportedDNSPolicies.List()))
+	}
+	if dnsPolicy == corev1.DNSNone && (dnsConfig == nil || len(dnsConfig.Nameservers) == 0) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("dnsConfig", "nameservers"), fmt.Sprintf("must provide at least one DNS nameserver when dnsPolicy is %s", corev1.DNSNone)))
+	}
+	if dnsConfig != nil {
+		for i, ns := range dnsConfig.Nameservers {
+			if net.ParseIP(ns) == nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsConfig", "nameservers").Index(i), ns, "must be a valid IP address"))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -299,9 +476,73 @@ func validateRequestsStorage(requests corev1.ResourceList, fldPath *field.Path)
 	return allErrs
 }
 
-//validateTiKVStorageSize validates resources requests storage
-func validateStorageVolumes(storageVolumes []v1alpha1.StorageVolume, fldPath *field.Path) field.ErrorList {
+// ValidateGuaranteedResources validates the resources of a container meet the requirements of the
+// Guaranteed QoS class, the request and the limit of cpu or memory are taken as each other if only
+// one of them is set. If exclusiveCPUs is true, the cpu must be an integer as well, which is required
+// by the static CPU manager policy of kubelet to allocate exclusive CPUs to the container.
+func ValidateGuaranteedResources(requirements corev1.ResourceRequirements, exclusiveCPUs bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := requirements.Requests[name]
+		limit, hasLimit := requirements.Limits[name]
+		if !hasRequest && !hasLimit {
+			allErrs = append(allErrs, field.Required(fldPath.Child("requests").Key(string(name)), "request or limit must be set for the Guaranteed QoS class"))
+			continue
+		}
+		if hasRequest && hasLimit && request.Cmp(limit) != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("limits").Key(string(name)), limit.String(), "limit must be equal to the request for the Guaranteed QoS class"))
+			continue
+		}
+		if !hasRequest {
+			request = limit
+		}
+		if name == corev1.ResourceCPU && exclusiveCPUs && request.MilliValue()%1000 != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(string(name)), request.String(), "cpu must be an integer for exclusive CPUs"))
+		}
+	}
+	return allErrs
+}
+
+// reservedMountPaths are the paths mounted by the operator in the component containers,
+// storage volumes must not be mounted at them.
+var reservedMountPaths = map[v1alpha1.MemberType][]string{
+	v1alpha1.PDMemberType: {
+		"/var/lib/pd", "/etc/pd", "/usr/local/bin", "/etc/podinfo",
+		"/var/lib/pd-tls", util.ClusterClientTLSPath, util.TiDBClientTLSPath,
+	},
+	v1alpha1.TiKVMemberType: {
+		"/var/lib/tikv", "/etc/tikv", "/usr/local/bin", "/etc/podinfo",
+		"/var/lib/tikv-tls", util.ClusterClientTLSPath,
+	},
+	v1alpha1.TiDBMemberType: {
+		"/etc/tidb", "/usr/local/bin", "/etc/podinfo",
+		"/var/lib/tidb-tls", "/var/lib/tidb-server-tls", "/var/lib/tidb-auth-token", "/etc/tidb-bootstrap",
+	},
+}
+
+// ValidateGuaranteedContainers validates the resources of the containers meet the requirements
+// of the Guaranteed QoS class, since all containers of the Pod, including the init containers,
+// must be Guaranteed for the Pod to be Guaranteed.
+func ValidateGuaranteedContainers(containers []corev1.Container, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, c := range containers {
+		allErrs = append(allErrs, ValidateGuaranteedResources(c.Resources, false, fldPath.Index(i).Child("resources"))...)
+	}
+	return allErrs
+}
+
+func validateNUMANode(node *int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if node != nil && *node < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *node, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+// validateStorageVolumes validates the storage volumes of a component
+func validateStorageVolumes(storageVolumes []v1alpha1.StorageVolume, memberType v1alpha1.MemberType, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	mountPaths := map[string]bool{}
 	for i, storageVolume := range storageVolumes {
 		idxPath := fldPath.Index(i)
 		if len(storageVolume.Name) == 0 {
@@ -316,8 +557,33 @@ func validateStorageVolumes(storageVolumes []v1alpha1.StorageVolume, fldPath *fi
 		}
 		if len(storageVolume.MountPath) == 0 {
 			allErrs = append(allErrs, field.Required(idxPath.Child("mountPath"), "mountPath must not be empty"))
+		} else {
+			allErrs = append(allErrs, validateStorageVolumeMountPath(storageVolume.MountPath, memberType, mountPaths, idxPath.Child("mountPath"))...)
+		}
+		if len(storageVolume.SubPath) > 0 {
+			allErrs = append(allErrs, validateLocalDescendingPath(storageVolume.SubPath, idxPath.Child("subPath"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateStorageVolumeMountPath makes sure the mountPath is an absolute path which is neither
+// reserved by the component nor used by another storage volume
+func validateStorageVolumeMountPath(mountPath string, memberType v1alpha1.MemberType, mountPaths map[string]bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !path.IsAbs(mountPath) {
+		return append(allErrs, field.Invalid(fldPath, mountPath, "must be an absolute path"))
+	}
+	cleaned := path.Clean(mountPath)
+	for _, reserved := range reservedMountPaths[memberType] {
+		if cleaned == reserved {
+			allErrs = append(allErrs, field.Invalid(fldPath, mountPath, fmt.Sprintf("%s is reserved by %s", reserved, memberType)))
 		}
 	}
+	if mountPaths[cleaned] {
+		allErrs = append(allErrs, field.Duplicate(fldPath, mountPath))
+	}
+	mountPaths[cleaned] = true
 	return allErrs
 }
 
@@ -454,7 +720,154 @@ func ValidateUpdateTidbCluster(old, tc *v1alpha1.TidbCluster) field.ErrorList {
 	}
 	allErrs = append(allErrs, validateUpdatePDConfig(old.Spec.PD.Config, tc.Spec.PD.Config, field.NewPath("spec.pd.config"))...)
 	allErrs = append(allErrs, disallowUsingLegacyAPIInNewCluster(old, tc)...)
+	allErrs = append(allErrs, validateUpdateBootstrapSQLSecret(old.Spec.TiDB, tc.Spec.TiDB, field.NewPath("spec.tidb.bootstrapSQLSecret"))...)
+	allErrs = append(allErrs, validateUpdateStorage(old, tc)...)
+	allErrs = append(allErrs, validateUpdateVersion(old, tc)...)
+	allErrs = append(allErrs, validateUpdateReplicas(old, tc)...)
+
+	return allErrs
+}
+
+// validateUpdateStorage rejects decreasing the storage size of the components, because
+// the persistent volumes can not be shrunk
+func validateUpdateStorage(old, tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+	if old.Spec.PD != nil && tc.Spec.PD != nil {
+		allErrs = append(allErrs, validateUpdateStorageRequest(old.Spec.PD.Requests, tc.Spec.PD.Requests, fldPath.Child("pd", "requests", "storage"))...)
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.PD.StorageVolumes, tc.Spec.PD.StorageVolumes, fldPath.Child("pd", "storageVolumes"))...)
+	}
+	if old.Spec.TiKV != nil && tc.Spec.TiKV != nil {
+		allErrs = append(allErrs, validateUpdateStorageRequest(old.Spec.TiKV.Requests, tc.Spec.TiKV.Requests, fldPath.Child("tikv", "requests", "storage"))...)
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageVolumes, fldPath.Child("tikv", "storageVolumes"))...)
+	}
+	if old.Spec.TiDB != nil && tc.Spec.TiDB != nil {
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.TiDB.StorageVolumes, tc.Spec.TiDB.StorageVolumes, fldPath.Child("tidb", "storageVolumes"))...)
+	}
+	if old.Spec.TiFlash != nil && tc.Spec.TiFlash != nil {
+		claimsPath := fldPath.Child("tiflash", "storageClaims")
+		for i, claim := range tc.Spec.TiFlash.StorageClaims {
+			if i >= len(old.Spec.TiFlash.StorageClaims) {
+				break
+			}
+			allErrs = append(allErrs, validateUpdateStorageRequest(old.Spec.TiFlash.StorageClaims[i].Resources.Requests, claim.Resources.Requests,
+				claimsPath.Index(i).Child("resources", "requests", "storage"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateUpdateStorageRequest(old, requests corev1.ResourceList, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oldSize, ok := old[corev1.ResourceStorage]
+	if !ok {
+		return allErrs
+	}
+	size, ok := requests[corev1.ResourceStorage]
+	if ok && size.Cmp(oldSize) < 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("can not be decreased from %s to %s, the persistent volumes can not be shrunk", oldSize.String(), size.String())))
+	}
+	return allErrs
+}
+
+func validateUpdateStorageVolumes(old, storageVolumes []v1alpha1.StorageVolume, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oldSizes := map[string]string{}
+	for _, sv := range old {
+		oldSizes[sv.Name] = sv.StorageSize
+	}
+	for i, sv := range storageVolumes {
+		oldSize, err := resource.ParseQuantity(oldSizes[sv.Name])
+		if err != nil {
+			continue
+		}
+		size, err := resource.ParseQuantity(sv.StorageSize)
+		if err != nil {
+			continue
+		}
+		if size.Cmp(oldSize) < 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("storageSize"), fmt.Sprintf("can not be decreased from %s to %s, the persistent volumes can not be shrunk", oldSize.String(), size.String())))
+		}
+	}
+	return allErrs
+}
+
+// validateUpdateVersion rejects downgrading PD and TiKV, whose data may not be compatible with the
+// older versions, unless it's allowed by the annotation explicitly. Versions which are not semantic
+// versions, e.g. latest or nightly, are not checked.
+func validateUpdateVersion(old, tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if tc.Annotations[label.AnnAllowVersionDowngrade] == label.AnnAllowVersionDowngradeVal {
+		return allErrs
+	}
+	if old.Spec.PD != nil && tc.Spec.PD != nil {
+		allErrs = append(allErrs, validateNotDowngraded(old.PDVersion(), tc.PDVersion(), field.NewPath("spec", "pd"))...)
+	}
+	if old.Spec.TiKV != nil && tc.Spec.TiKV != nil {
+		allErrs = append(allErrs, validateNotDowngraded(old.TiKVVersion(), tc.TiKVVersion(), field.NewPath("spec", "tikv"))...)
+	}
+	return allErrs
+}
+
+func validateNotDowngraded(oldVersion, version string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ov, err := semver.NewVersion(oldVersion)
+	if err != nil {
+		return allErrs
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return allErrs
+	}
+	if v.LessThan(ov) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("downgrading from %s to %s is not allowed, set annotation %s to %q to allow it",
+			oldVersion, version, label.AnnAllowVersionDowngrade, label.AnnAllowVersionDowngradeVal)))
+	}
+	return allErrs
+}
+
+// defaultMaxReplicas is the default replicas of regions in PD replication config
+const defaultMaxReplicas = 3
+
+// validateUpdateReplicas rejects scaling in PD to 0, and scaling in TiKV to less than the replicas
+// of regions, otherwise the stores can not be removed since there is no place for the replicas on them.
+// The replicas of regions is only known if PD is managed by the TidbCluster.
+func validateUpdateReplicas(old, tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if tc.Spec.PD == nil {
+		return allErrs
+	}
+	if old.Spec.PD != nil && old.Spec.PD.Replicas > 0 && tc.Spec.PD.Replicas < 1 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "pd", "replicas"), "can not be scaled in to 0"))
+	}
+	if old.Spec.TiKV == nil || tc.Spec.TiKV == nil || tc.Spec.TiKV.Replicas >= old.Spec.TiKV.Replicas {
+		return allErrs
+	}
+	maxReplicas := int64(defaultMaxReplicas)
+	if tc.Spec.PD.Config != nil {
+		if v := tc.Spec.PD.Config.Get("replication.max-replicas"); v != nil {
+			if n, err := v.AsInt(); err == nil {
+				maxReplicas = n
+			}
+		}
+	}
+	if int64(tc.Spec.TiKV.Replicas) < maxReplicas {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "tikv", "replicas"),
+			fmt.Sprintf("can not be scaled in to less than %d, which is the max-replicas of PD replication config", maxReplicas)))
+	}
+	return allErrs
+}
 
+// validateUpdateBootstrapSQLSecret disallows changing the bootstrap SQL to another secret after the cluster
+// is created, because it is only executed when the cluster is bootstrapped. Unsetting it is allowed.
+func validateUpdateBootstrapSQLSecret(old, tidb *v1alpha1.TiDBSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if old == nil || tidb == nil || tidb.BootstrapSQLSecret == nil {
+		return allErrs
+	}
+	if !reflect.DeepEqual(old.BootstrapSQLSecret, tidb.BootstrapSQLSecret) {
+		allErrs = append(allErrs, field.Forbidden(path, "bootstrapSQLSecret can not be changed after the cluster is created"))
+	}
 	return allErrs
 }
 
@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -569,6 +570,239 @@ func TestValidateLocalDescendingPath(t *testing.T) {
 	}
 }
 
+func TestValidateStorageVolumes(t *testing.T) {
+	successCases := [][]v1alpha1.StorageVolume{
+		{
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/raft"},
+			{Name: "wal", StorageSize: "1Gi", MountPath: "/var/lib/wal", SubPath: "tikv/wal"},
+		},
+		{
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/tikv/raft"},
+		},
+	}
+
+	for _, c := range successCases {
+		errs := validateStorageVolumes(c, v1alpha1.TiKVMemberType, field.NewPath("storageVolumes"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string][]v1alpha1.StorageVolume{
+		"relative mountPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "var/lib/raft"},
+		},
+		"reserved mountPath": {
+			{Name: "data", StorageSize: "1Gi", MountPath: "/var/lib/tikv/"},
+		},
+		"podinfo mountPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/etc/podinfo"},
+		},
+		"tls mountPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/tikv-tls"},
+		},
+		"cluster client tls mountPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/cluster-client-tls"},
+		},
+		"duplicated mountPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/raft"},
+			{Name: "wal", StorageSize: "1Gi", MountPath: "/var/lib/raft"},
+		},
+		"absolute subPath": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/raft", SubPath: "/raft"},
+		},
+		"subPath with backsteps": {
+			{Name: "raft", StorageSize: "1Gi", MountPath: "/var/lib/raft", SubPath: "../raft"},
+		},
+	}
+
+	for name, c := range errorCases {
+		errs := validateStorageVolumes(c, v1alpha1.TiKVMemberType, field.NewPath("storageVolumes"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %s", name)
+		}
+	}
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(validateFeatureGates(nil, field.NewPath("featureGates"))).To(BeEmpty())
+	g.Expect(validateFeatureGates(map[string]bool{features.StableScheduling: false}, field.NewPath("featureGates"))).To(BeEmpty())
+	// the operator scoped features can not be overridden by a cluster
+	errs := validateFeatureGates(map[string]bool{features.AdvancedStatefulSet: true}, field.NewPath("featureGates"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	g.Expect(errs[0].Field).To(Equal("featureGates[AdvancedStatefulSet]"))
+}
+
+func TestValidateDNS(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fldPath := field.NewPath("spec", "tidb")
+
+	g.Expect(validateDNS("", nil, fldPath)).To(BeEmpty())
+	g.Expect(validateDNS(corev1.DNSClusterFirstWithHostNet, nil, fldPath)).To(BeEmpty())
+	g.Expect(validateDNS(corev1.DNSNone, &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}}, fldPath)).To(BeEmpty())
+
+	errs := validateDNS("Unknown", nil, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	errs = validateDNS(corev1.DNSDefault, nil, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+
+	errs = validateDNS(corev1.DNSNone, nil, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.tidb.dnsConfig.nameservers"))
+
+	errs = validateDNS(corev1.DNSClusterFirst, &corev1.PodDNSConfig{Nameservers: []string{"dns.local"}}, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.tidb.dnsConfig.nameservers[0]"))
+}
+
+func TestValidatePodLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fldPath := field.NewPath("spec", "tidb", "labels")
+
+	g.Expect(validatePodLabels(nil, fldPath)).To(BeEmpty())
+	g.Expect(validatePodLabels(map[string]string{"sidecar.istio.io/inject": "true"}, fldPath)).To(BeEmpty())
+
+	errs := validatePodLabels(map[string]string{"app.kubernetes.io/component": "tikv"}, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	errs = validatePodLabels(map[string]string{"tidb.pingcap.com/store-id": "1"}, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.tidb.labels[tidb.pingcap.com/store-id]"))
+
+	errs = validatePodLabels(map[string]string{"team": "a b"}, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+}
+
+func TestValidateProbe(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fldPath := field.NewPath("spec", "tikv", "readinessProbe")
+
+	g.Expect(validateProbe(nil, tcpProbeTypes, fldPath)).To(BeEmpty())
+	g.Expect(validateProbe(&v1alpha1.Probe{
+		Type:                pointer.StringPtr(v1alpha1.TCPProbeType),
+		InitialDelaySeconds: pointer.Int32Ptr(0),
+		PeriodSeconds:       pointer.Int32Ptr(30),
+		FailureThreshold:    pointer.Int32Ptr(10),
+	}, tcpProbeTypes, fldPath)).To(BeEmpty())
+
+	errs := validateProbe(&v1alpha1.Probe{Type: pointer.StringPtr("grpc")}, tcpProbeTypes, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+
+	// command and http are only supported by tidb
+	for _, tp := range []string{v1alpha1.CommandProbeType, v1alpha1.HTTPProbeType} {
+		errs = validateProbe(&v1alpha1.Probe{Type: pointer.StringPtr(tp)}, tcpProbeTypes, fldPath)
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		g.Expect(validateProbe(&v1alpha1.Probe{Type: pointer.StringPtr(tp)}, tidbProbeTypes, fldPath)).To(BeEmpty())
+	}
+
+	errs = validateProbe(&v1alpha1.Probe{
+		InitialDelaySeconds: pointer.Int32Ptr(-1),
+		TimeoutSeconds:      pointer.Int32Ptr(0),
+	}, tcpProbeTypes, fldPath)
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.tikv.readinessProbe.initialDelaySeconds"))
+	g.Expect(errs[1].Field).To(Equal("spec.tikv.readinessProbe.timeoutSeconds"))
+}
+
+func TestValidateTerminationGracePeriodSeconds(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fldPath := field.NewPath("spec", "tikv")
+
+	g.Expect(validateComponentSpec(&v1alpha1.ComponentSpec{TerminationGracePeriodSeconds: pointer.Int64Ptr(3600)}, fldPath)).To(BeEmpty())
+
+	errs := validateComponentSpec(&v1alpha1.ComponentSpec{TerminationGracePeriodSeconds: pointer.Int64Ptr(-1)}, fldPath)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.tikv.terminationGracePeriodSeconds"))
+}
+
+func TestValidateUpdateStorage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	newTC := func(pdStorage, tikvStorage, logStorage string) *v1alpha1.TidbCluster {
+		tc := &v1alpha1.TidbCluster{}
+		tc.Spec.PD = &v1alpha1.PDSpec{}
+		tc.Spec.PD.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(pdStorage)}
+		tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+		tc.Spec.TiKV.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(tikvStorage)}
+		tc.Spec.TiKV.StorageVolumes = []v1alpha1.StorageVolume{{Name: "log", StorageSize: logStorage}}
+		return tc
+	}
+
+	g.Expect(validateUpdateStorage(newTC("1Gi", "10Gi", "1Gi"), newTC("1Gi", "20Gi", "2Gi"))).To(BeEmpty())
+
+	errs := validateUpdateStorage(newTC("1Gi", "10Gi", "1Gi"), newTC("1Gi", "5Gi", "1Gi"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	g.Expect(errs[0].Field).To(Equal("spec.tikv.requests.storage"))
+
+	errs = validateUpdateStorage(newTC("1Gi", "10Gi", "2Gi"), newTC("1Gi", "10Gi", "1Gi"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.tikv.storageVolumes[0].storageSize"))
+}
+
+func TestValidateUpdateVersion(t *testing.T) {
+	g := NewGomegaWithT(t)
+	newTC := func(version string) *v1alpha1.TidbCluster {
+		tc := &v1alpha1.TidbCluster{}
+		tc.Spec.Version = version
+		tc.Spec.PD = &v1alpha1.PDSpec{}
+		tc.Spec.PD.BaseImage = "pingcap/pd"
+		tc.Spec.TiKV = &v1alpha1.TiKVSpec{}
+		tc.Spec.TiKV.BaseImage = "pingcap/tikv"
+		return tc
+	}
+
+	g.Expect(validateUpdateVersion(newTC("v4.0.9"), newTC("v5.0.1"))).To(BeEmpty())
+	g.Expect(validateUpdateVersion(newTC("v5.0.1"), newTC("nightly"))).To(BeEmpty())
+
+	errs := validateUpdateVersion(newTC("v5.0.1"), newTC("v4.0.9"))
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.pd"))
+	g.Expect(errs[1].Field).To(Equal("spec.tikv"))
+
+	tc := newTC("v4.0.9")
+	tc.Annotations = map[string]string{label.AnnAllowVersionDowngrade: label.AnnAllowVersionDowngradeVal}
+	g.Expect(validateUpdateVersion(newTC("v5.0.1"), tc)).To(BeEmpty())
+}
+
+func TestValidateUpdateReplicas(t *testing.T) {
+	g := NewGomegaWithT(t)
+	newTC := func(pdReplicas, tikvReplicas int32) *v1alpha1.TidbCluster {
+		tc := &v1alpha1.TidbCluster{}
+		tc.Spec.PD = &v1alpha1.PDSpec{Replicas: pdReplicas}
+		tc.Spec.TiKV = &v1alpha1.TiKVSpec{Replicas: tikvReplicas}
+		return tc
+	}
+
+	g.Expect(validateUpdateReplicas(newTC(3, 5), newTC(3, 3))).To(BeEmpty())
+	// scaling out a cluster with less TiKV than max-replicas is allowed
+	g.Expect(validateUpdateReplicas(newTC(1, 1), newTC(1, 2))).To(BeEmpty())
+
+	errs := validateUpdateReplicas(newTC(3, 5), newTC(0, 2))
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.pd.replicas"))
+	g.Expect(errs[1].Field).To(Equal("spec.tikv.replicas"))
+
+	tc := newTC(3, 2)
+	tc.Spec.PD.Config = v1alpha1.NewPDConfig()
+	tc.Spec.PD.Config.Set("replication.max-replicas", 1)
+	g.Expect(validateUpdateReplicas(newTC(3, 5), tc)).To(BeEmpty())
+
+	// PD is not managed by the tc, e.g. TiKV joins the cluster of another tc
+	old := newTC(3, 5)
+	old.Spec.PD = nil
+	tc = newTC(3, 1)
+	tc.Spec.PD = nil
+	g.Expect(validateUpdateReplicas(old, tc)).To(BeEmpty())
+}
+
 func TestValidateEvictLeaderTimeout(t *testing.T) {
 	successCases := []*string{
 		nil,
@@ -632,3 +866,271 @@ func TestValidatePDAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateUpdateBootstrapSQLSecret(t *testing.T) {
+	successCases := []struct {
+		old  *v1alpha1.TiDBSpec
+		tidb *v1alpha1.TiDBSpec
+	}{
+		{
+			old:  &v1alpha1.TiDBSpec{},
+			tidb: &v1alpha1.TiDBSpec{},
+		},
+		{
+			old:  &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+			tidb: &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+		},
+		{
+			old:  nil,
+			tidb: &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+		},
+		{
+			old:  &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+			tidb: &v1alpha1.TiDBSpec{},
+		},
+	}
+
+	for _, c := range successCases {
+		errs := validateUpdateBootstrapSQLSecret(c.old, c.tidb, field.NewPath("bootstrapSQLSecret"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := []struct {
+		old  *v1alpha1.TiDBSpec
+		tidb *v1alpha1.TiDBSpec
+	}{
+		{
+			old:  &v1alpha1.TiDBSpec{},
+			tidb: &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+		},
+		{
+			old:  &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("bootstrap")},
+			tidb: &v1alpha1.TiDBSpec{BootstrapSQLSecret: pointer.StringPtr("another")},
+		},
+	}
+
+	for _, c := range errorCases {
+		errs := validateUpdateBootstrapSQLSecret(c.old, c.tidb, field.NewPath("bootstrapSQLSecret"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v -> %v", c.old.BootstrapSQLSecret, c.tidb.BootstrapSQLSecret)
+		}
+	}
+}
+
+func TestValidateGuaranteedResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		requirements   corev1.ResourceRequirements
+		exclusiveCPUs  bool
+		expectedErrors int
+	}{
+		{
+			name: "requests only",
+			requirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			exclusiveCPUs: true,
+		},
+		{
+			name: "limits only",
+			requirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4000m"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			exclusiveCPUs: true,
+		},
+		{
+			name: "fractional cpu for sidecar",
+			requirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("50Mi"),
+				},
+			},
+		},
+		{
+			name: "fractional cpu",
+			requirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("3500m"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			exclusiveCPUs:  true,
+			expectedErrors: 1,
+		},
+		{
+			name: "limits not equal to requests",
+			requirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+			},
+			exclusiveCPUs:  true,
+			expectedErrors: 2,
+		},
+		{
+			name:           "empty resources",
+			exclusiveCPUs:  true,
+			expectedErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateGuaranteedResources(tt.requirements, tt.exclusiveCPUs, field.NewPath("spec", "tikv"))
+			g.Expect(len(errs)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateTiKVSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		update         func(*v1alpha1.TiKVSpec)
+		expectedErrors int
+	}{
+		{
+			name:   "default",
+			update: func(spec *v1alpha1.TiKVSpec) {},
+		},
+		{
+			name:   "valid updatePartition",
+			update: func(spec *v1alpha1.TiKVSpec) { spec.UpdatePartition = pointer.Int32Ptr(3) },
+		},
+		{
+			name:           "negative updatePartition",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.UpdatePartition = pointer.Int32Ptr(-1) },
+			expectedErrors: 1,
+		},
+		{
+			name:           "updatePartition greater than replicas",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.UpdatePartition = pointer.Int32Ptr(4) },
+			expectedErrors: 1,
+		},
+		{
+			name:   "valid warmUpRegionPercent",
+			update: func(spec *v1alpha1.TiKVSpec) { spec.WarmUpRegionPercent = pointer.Int32Ptr(80) },
+		},
+		{
+			name:           "zero warmUpRegionPercent",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.WarmUpRegionPercent = pointer.Int32Ptr(0) },
+			expectedErrors: 1,
+		},
+		{
+			name:           "100 warmUpRegionPercent",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.WarmUpRegionPercent = pointer.Int32Ptr(100) },
+			expectedErrors: 1,
+		},
+		{
+			name:   "100 upgradeDiskUsageThreshold",
+			update: func(spec *v1alpha1.TiKVSpec) { spec.UpgradeDiskUsageThreshold = pointer.Int32Ptr(100) },
+		},
+		{
+			name:           "zero upgradeDiskUsageThreshold",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.UpgradeDiskUsageThreshold = pointer.Int32Ptr(0) },
+			expectedErrors: 1,
+		},
+		{
+			name:           "upgradeDiskUsageThreshold greater than 100",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.UpgradeDiskUsageThreshold = pointer.Int32Ptr(101) },
+			expectedErrors: 1,
+		},
+		{
+			name: "valid storeWeight",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.StoreWeight = &v1alpha1.StoreWeight{Leader: pointer.StringPtr("1.5"), Region: pointer.StringPtr("0.5")}
+			},
+		},
+		{
+			name: "invalid storeWeight",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.StoreWeight = &v1alpha1.StoreWeight{Leader: pointer.StringPtr("0"), Region: pointer.StringPtr("-1")}
+			},
+			expectedErrors: 2,
+		},
+		{
+			name: "valid canary",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.Canary = &v1alpha1.CanarySpec{Replicas: 1, AutoPromoteAfter: pointer.StringPtr("10m")}
+			},
+		},
+		{
+			name:           "negative canary replicas",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.Canary = &v1alpha1.CanarySpec{Replicas: -1} },
+			expectedErrors: 1,
+		},
+		{
+			name:           "canary replicas greater than replicas",
+			update:         func(spec *v1alpha1.TiKVSpec) { spec.Canary = &v1alpha1.CanarySpec{Replicas: 4} },
+			expectedErrors: 1,
+		},
+		{
+			name: "negative numaNode",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.CPUPinning = &v1alpha1.CPUPinning{NUMANode: pointer.Int32Ptr(-1)}
+			},
+			expectedErrors: 1,
+		},
+		{
+			name: "cpu pinning with guaranteed containers",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.CPUPinning = &v1alpha1.CPUPinning{Enabled: true, NUMANode: pointer.Int32Ptr(0)}
+				spec.Requests[corev1.ResourceCPU] = resource.MustParse("4")
+				spec.Requests[corev1.ResourceMemory] = resource.MustParse("8Gi")
+				resources := corev1.ResourceRequirements{Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("50Mi"),
+				}}
+				spec.InitContainers = []corev1.Container{{Name: "init", Resources: resources}}
+				spec.AdditionalContainers = []corev1.Container{{Name: "sidecar", Resources: resources}}
+			},
+		},
+		{
+			name: "cpu pinning with non-guaranteed containers",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.CPUPinning = &v1alpha1.CPUPinning{Enabled: true}
+				spec.Requests[corev1.ResourceCPU] = resource.MustParse("4")
+				spec.Requests[corev1.ResourceMemory] = resource.MustParse("8Gi")
+				spec.InitContainers = []corev1.Container{{Name: "init"}}
+				spec.AdditionalContainers = []corev1.Container{{Name: "sidecar"}}
+			},
+			expectedErrors: 4,
+		},
+		{
+			name: "non-numeric storeWeight",
+			update: func(spec *v1alpha1.TiKVSpec) {
+				spec.StoreWeight = &v1alpha1.StoreWeight{Leader: pointer.StringPtr("heavy")}
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		spec := &v1alpha1.TiKVSpec{
+			Replicas: 3,
+			ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10Gi"),
+				},
+			},
+		}
+		tt.update(spec)
+		errs := validateTiKVSpec(spec, field.NewPath("spec", "tikv"))
+		g.Expect(errs).To(HaveLen(tt.expectedErrors), "%v", errs)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	v1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
 )
@@ -546,6 +547,48 @@ func (in *Binlog) DeepCopy() *Binlog {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUPinning) DeepCopyInto(out *CPUPinning) {
+	*out = *in
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPinning.
+func (in *CPUPinning) DeepCopy() *CPUPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
+	*out = *in
+	if in.AutoPromoteAfter != nil {
+		in, out := &in.AutoPromoteAfter, &out.AutoPromoteAfter
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
+func (in *CanarySpec) DeepCopy() *CanarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterRef) DeepCopyInto(out *ClusterRef) {
 	*out = *in
@@ -711,6 +754,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(v1.Affinity)
@@ -740,6 +788,13 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -747,6 +802,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]TopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -797,6 +857,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1067,6 +1132,11 @@ func (in *DMClusterSpec) DeepCopyInto(out *DMClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]TopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -3254,6 +3324,11 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 	*out = *in
 	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.PodSpecOverlay != nil {
+		in, out := &in.PodSpecOverlay, &out.PodSpecOverlay
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
 		*out = new(ServiceSpec)
@@ -3622,6 +3697,52 @@ func (in *PreparedPlanCache) DeepCopy() *PreparedPlanCache {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Profile) DeepCopyInto(out *Profile) {
 	*out = *in
@@ -3861,6 +3982,11 @@ func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
 	}
+	if in.OfflineTimeout != nil {
+		in, out := &in.OfflineTimeout, &out.OfflineTimeout
+		*out = new(string)
+		**out = **in
+	}
 	if in.SetTimeZone != nil {
 		in, out := &in.SetTimeZone, &out.SetTimeZone
 		*out = new(bool)
@@ -3937,6 +4063,23 @@ func (in *Quota) DeepCopy() *Quota {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileStatus) DeepCopyInto(out *ReconcileStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileStatus.
+func (in *ReconcileStatus) DeepCopy() *ReconcileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
 	*out = *in
@@ -4546,6 +4689,32 @@ func (in *StorageVolume) DeepCopy() *StorageVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoreWeight) DeepCopyInto(out *StoreWeight) {
+	*out = *in
+	if in.Leader != nil {
+		in, out := &in.Leader, &out.Leader
+		*out = new(string)
+		**out = **in
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreWeight.
+func (in *StoreWeight) DeepCopy() *StoreWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(StoreWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSCluster) DeepCopyInto(out *TLSCluster) {
 	*out = *in
@@ -4690,6 +4859,11 @@ func (in *TiCDCSpec) DeepCopyInto(out *TiCDCSpec) {
 		*out = new(TiCDCConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GracefulShutdownTimeout != nil {
+		in, out := &in.GracefulShutdownTimeout, &out.GracefulShutdownTimeout
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -5049,27 +5223,6 @@ func (in *TiDBMember) DeepCopy() *TiDBMember {
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBProbe) DeepCopyInto(out *TiDBProbe) {
-	*out = *in
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(string)
-		**out = **in
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBProbe.
-func (in *TiDBProbe) DeepCopy() *TiDBProbe {
-	if in == nil {
-		return nil
-	}
-	out := new(TiDBProbe)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBServiceSpec) DeepCopyInto(out *TiDBServiceSpec) {
 	*out = *in
@@ -5144,6 +5297,11 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 	*out = *in
 	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.PodSpecOverlay != nil {
+		in, out := &in.PodSpecOverlay, &out.PodSpecOverlay
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
 		*out = new(TiDBServiceSpec)
@@ -5174,6 +5332,26 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(TiDBTLSClient)
 		**out = **in
 	}
+	if in.AuthTokenJWKSSecret != nil {
+		in, out := &in.AuthTokenJWKSSecret, &out.AuthTokenJWKSSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.SQLHealthCheckSecret != nil {
+		in, out := &in.SQLHealthCheckSecret, &out.SQLHealthCheckSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.BootstrapSQLSecret != nil {
+		in, out := &in.BootstrapSQLSecret, &out.BootstrapSQLSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.CPUPinning != nil {
+		in, out := &in.CPUPinning, &out.CPUPinning
+		*out = new(CPUPinning)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugins != nil {
 		in, out := &in.Plugins, &out.Plugins
 		*out = make([]string, len(*in))
@@ -5201,11 +5379,6 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
-		*out = new(TiDBProbe)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
@@ -5316,6 +5489,23 @@ func (in *TiFlashConfig) DeepCopy() *TiFlashConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashConfigReloaderSpec) DeepCopyInto(out *TiFlashConfigReloaderSpec) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashConfigReloaderSpec.
+func (in *TiFlashConfigReloaderSpec) DeepCopy() *TiFlashConfigReloaderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashConfigReloaderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiFlashConfigWraper) DeepCopyInto(out *TiFlashConfigWraper) {
 	*out = *in
@@ -5394,6 +5584,11 @@ func (in *TiFlashSpec) DeepCopyInto(out *TiFlashSpec) {
 		*out = new(LogTailerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigReloader != nil {
+		in, out := &in.ConfigReloader, &out.ConfigReloader
+		*out = new(TiFlashConfigReloaderSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -7188,6 +7383,11 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 	*out = *in
 	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.PodSpecOverlay != nil {
+		in, out := &in.PodSpecOverlay, &out.PodSpecOverlay
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Privileged != nil {
 		in, out := &in.Privileged, &out.Privileged
 		*out = new(bool)
@@ -7233,6 +7433,46 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.UpdatePartition != nil {
+		in, out := &in.UpdatePartition, &out.UpdatePartition
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeHooks != nil {
+		in, out := &in.UpgradeHooks, &out.UpgradeHooks
+		*out = new(UpgradeHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmUpRegionPercent != nil {
+		in, out := &in.WarmUpRegionPercent, &out.WarmUpRegionPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowStoreProtection != nil {
+		in, out := &in.SlowStoreProtection, &out.SlowStoreProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UpgradeDiskUsageThreshold != nil {
+		in, out := &in.UpgradeDiskUsageThreshold, &out.UpgradeDiskUsageThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StoreWeight != nil {
+		in, out := &in.StoreWeight, &out.StoreWeight
+		*out = new(StoreWeight)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUPinning != nil {
+		in, out := &in.CPUPinning, &out.CPUPinning
+		*out = new(CPUPinning)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageVolumes != nil {
 		in, out := &in.StorageVolumes, &out.StorageVolumes
 		*out = make([]StorageVolume, len(*in))
@@ -7294,6 +7534,14 @@ func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	in.CanaryUpdatedAt.DeepCopyInto(&out.CanaryUpdatedAt)
+	if in.EvictLeaderStores != nil {
+		in, out := &in.EvictLeaderStores, &out.EvictLeaderStores
+		*out = make(map[string]metav1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -7419,6 +7667,10 @@ func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
 	*out = *in
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.SlowSince != nil {
+		in, out := &in.SlowSince, &out.SlowSince
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -7938,6 +8190,11 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]TopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]Service, len(*in))
@@ -7958,6 +8215,18 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnablePodDisruptionBudget != nil {
+		in, out := &in.EnablePodDisruptionBudget, &out.EnablePodDisruptionBudget
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -7997,6 +8266,11 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastReconcile != nil {
+		in, out := &in.LastReconcile, &out.LastReconcile
+		*out = new(ReconcileStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8398,6 +8672,22 @@ func (in *TikvAutoScalerStatus) DeepCopy() *TikvAutoScalerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpreadConstraint) DeepCopyInto(out *TopologySpreadConstraint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpreadConstraint.
+func (in *TopologySpreadConstraint) DeepCopy() *TopologySpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TxnLocalLatches) DeepCopyInto(out *TxnLocalLatches) {
 	*out = *in
@@ -8448,6 +8738,32 @@ func (in *UnjoinedMember) DeepCopy() *UnjoinedMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHooks) DeepCopyInto(out *UpgradeHooks) {
+	*out = *in
+	if in.PreUpgrade != nil {
+		in, out := &in.PreUpgrade, &out.PreUpgrade
+		*out = new(v1.Container)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUpgrade != nil {
+		in, out := &in.PostUpgrade, &out.PostUpgrade
+		*out = new(v1.Container)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHooks.
+func (in *UpgradeHooks) DeepCopy() *UpgradeHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
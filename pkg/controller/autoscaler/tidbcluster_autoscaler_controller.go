@@ -39,7 +39,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 		deps:    deps,
 		control: NewDefaultAutoScalerControl(autoscaler.NewAutoScalerManager(deps)),
 		queue: workqueue.NewNamedRateLimitingQueue(
-			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
+			controller.NewControllerRateLimiter(deps.CLIConfig.RateLimiterBaseDelay, deps.CLIConfig.RateLimiterMaxDelay, deps.CLIConfig.RateLimiterQPS, deps.CLIConfig.RateLimiterBurst),
 			"tidbclusterautoscaler",
 		),
 	}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -61,16 +62,35 @@ var (
 
 // RequeueError is used to requeue the item, this error type should't be considered as a real error
 type RequeueError struct {
-	s string
+	s        string
+	reason   string
+	duration time.Duration
 }
 
 func (re *RequeueError) Error() string {
 	return re.s
 }
 
+// Reason returns the short CamelCase reason of what is waited for, empty if not set
+func (re *RequeueError) Reason() string {
+	return re.reason
+}
+
+// Duration returns the delay after which the item is requeued, 0 means the item
+// is requeued by the rate limiter of the queue
+func (re *RequeueError) Duration() time.Duration {
+	return re.duration
+}
+
 // RequeueErrorf returns a RequeueError
 func RequeueErrorf(format string, a ...interface{}) error {
-	return &RequeueError{fmt.Sprintf(format, a...)}
+	return &RequeueError{s: fmt.Sprintf(format, a...)}
+}
+
+// RequeueAfterErrorf returns a RequeueError which requeues the item after the duration,
+// the reason tells users what is waited for, e.g. WaitingForLeaderEviction
+func RequeueAfterErrorf(duration time.Duration, reason string, format string, a ...interface{}) error {
+	return &RequeueError{s: fmt.Sprintf(format, a...), reason: reason, duration: duration}
 }
 
 // IsRequeueError returns whether err is a RequeueError
@@ -16,6 +16,7 @@ package controller
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -38,6 +39,14 @@ func TestRequeueError(t *testing.T) {
 	g.Expect(ok).To(BeTrue())
 	g.Expect(err.Error()).To(Equal("i am a requeue error"))
 	g.Expect(IsRequeueError(fmt.Errorf("i am not a requeue error"))).To(BeFalse())
+	g.Expect(err.(*RequeueError).Duration()).To(BeZero())
+	g.Expect(err.(*RequeueError).Reason()).To(BeEmpty())
+
+	err = RequeueAfterErrorf(10*time.Second, "WaitingForPodReady", "pod %s is not ready", "demo-tikv-0")
+	g.Expect(IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(Equal("pod demo-tikv-0 is not ready"))
+	g.Expect(err.(*RequeueError).Duration()).To(Equal(10 * time.Second))
+	g.Expect(err.(*RequeueError).Reason()).To(Equal("WaitingForPodReady"))
 }
 
 func TestIgnoreError(t *testing.T) {
@@ -15,6 +15,10 @@ package controller
 
 import (
 	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -36,6 +40,7 @@ import (
 	batchlisters "k8s.io/client-go/listers/batch/v1"
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
 	extensionslister "k8s.io/client-go/listers/extensions/v1beta1"
+	policylisters "k8s.io/client-go/listers/policy/v1beta1"
 	storagelister "k8s.io/client-go/listers/storage/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
@@ -50,6 +55,17 @@ type CLIConfig struct {
 	// Larger number = more responsive management, but more CPU
 	// (and network) load
 	Workers int
+	// ControllerWorkers overrides Workers for the controllers in it,
+	// keyed by controller name
+	ControllerWorkers map[string]int
+	// RateLimiterBaseDelay and RateLimiterMaxDelay bound the per-item
+	// exponential backoff of the controller workqueues
+	RateLimiterBaseDelay time.Duration
+	RateLimiterMaxDelay  time.Duration
+	// RateLimiterQPS and RateLimiterBurst limit the overall retry rate
+	// of each controller workqueue
+	RateLimiterQPS   float64
+	RateLimiterBurst int
 	// Controls whether operator should manage kubernetes cluster
 	// wide TiDB clusters
 	ClusterScoped         bool
@@ -83,6 +99,11 @@ type CLIConfig struct {
 func DefaultCLIConfig() *CLIConfig {
 	return &CLIConfig{
 		Workers:                5,
+		ControllerWorkers:      map[string]int{},
+		RateLimiterBaseDelay:   1 * time.Second,
+		RateLimiterMaxDelay:    100 * time.Second,
+		RateLimiterQPS:         10,
+		RateLimiterBurst:       100,
 		ClusterScoped:          true,
 		AutoFailover:           true,
 		PDFailoverPeriod:       5 * time.Minute,
@@ -107,6 +128,11 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.BoolVar(&c.PrintVersion, "V", false, "Show version and quit")
 	flag.BoolVar(&c.PrintVersion, "version", false, "Show version and quit")
 	flag.IntVar(&c.Workers, "workers", c.Workers, "The number of workers that are allowed to sync concurrently. Larger number = more responsive management, but more CPU (and network) load")
+	flag.Var((*controllerWorkers)(&c.ControllerWorkers), "controller-workers", "Comma-separated list of controller=workers pairs that override --workers for the given controllers, e.g. tidbcluster=10,backup=2")
+	flag.DurationVar(&c.RateLimiterBaseDelay, "rate-limiter-base-delay", c.RateLimiterBaseDelay, "The base delay of the per-item exponential backoff when a controller requeues an object")
+	flag.DurationVar(&c.RateLimiterMaxDelay, "rate-limiter-max-delay", c.RateLimiterMaxDelay, "The max delay of the per-item exponential backoff when a controller requeues an object")
+	flag.Float64Var(&c.RateLimiterQPS, "rate-limiter-qps", c.RateLimiterQPS, "The overall qps of requeues of each controller workqueue")
+	flag.IntVar(&c.RateLimiterBurst, "rate-limiter-burst", c.RateLimiterBurst, "The overall burst of requeues of each controller workqueue")
 	flag.BoolVar(&c.ClusterScoped, "cluster-scoped", c.ClusterScoped, "Whether tidb-operator should manage kubernetes cluster wide TiDB Clusters")
 	flag.BoolVar(&c.AutoFailover, "auto-failover", c.AutoFailover, "Auto failover")
 	flag.DurationVar(&c.PDFailoverPeriod, "pd-failover-period", c.PDFailoverPeriod, "PD failover period default(5m)")
@@ -129,6 +155,61 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.DurationVar(&c.RetryPeriod, "leader-retry-period", c.RetryPeriod, "leader-retry-period is the duration the LeaderElector clients should wait between tries of actions")
 }
 
+// Validate validates the command line configuration
+func (c *CLIConfig) Validate() error {
+	if c.RateLimiterQPS <= 0 {
+		return fmt.Errorf("invalid --rate-limiter-qps %v, must be positive", c.RateLimiterQPS)
+	}
+	if c.RateLimiterBurst <= 0 {
+		return fmt.Errorf("invalid --rate-limiter-burst %d, must be positive", c.RateLimiterBurst)
+	}
+	return nil
+}
+
+// WorkersOf returns the number of workers of the controller with the given name
+func (c *CLIConfig) WorkersOf(name string) int {
+	if w, ok := c.ControllerWorkers[name]; ok {
+		return w
+	}
+	return c.Workers
+}
+
+// controllerWorkers implements flag.Value for a map of controller name to workers
+type controllerWorkers map[string]int
+
+func (w *controllerWorkers) String() string {
+	if w == nil || *w == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*w))
+	for name, n := range *w {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (w *controllerWorkers) Set(value string) error {
+	m := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid controller workers %q, must be controller=workers", pair)
+		}
+		n, err := strconv.Atoi(kv[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid workers %q of controller %s, must be a positive integer", kv[1], kv[0])
+		}
+		m[kv[0]] = n
+	}
+	*w = m
+	return nil
+}
+
 type Controls struct {
 	JobControl         JobControlInterface
 	ConfigMapControl   ConfigMapControlInterface
@@ -146,6 +227,7 @@ type Controls struct {
 	TiDBClusterControl TidbClusterControlInterface
 	DMClusterControl   DMClusterControlInterface
 	CDCControl         TiCDCControlInterface
+	PumpControl        PumpControlInterface
 	TiDBControl        TiDBControlInterface
 	BackupControl      BackupControlInterface
 }
@@ -178,6 +260,7 @@ type Dependencies struct {
 	DeploymentLister            appslisters.DeploymentLister
 	JobLister                   batchlisters.JobLister
 	IngressLister               extensionslister.IngressLister
+	PDBLister                   policylisters.PodDisruptionBudgetLister
 	StorageClassLister          storagelister.StorageClassLister
 	TiDBClusterLister           listers.TidbClusterLister
 	TiDBClusterAutoScalerLister listers.TidbClusterAutoScalerLister
@@ -230,6 +313,7 @@ func newRealControls(
 		TiDBClusterControl: NewRealTidbClusterControl(clientset, tidbClusterLister, recorder),
 		DMClusterControl:   NewRealDMClusterControl(clientset, dmClusterLister, recorder),
 		CDCControl:         NewDefaultTiCDCControl(kubeClientset),
+		PumpControl:        NewDefaultPumpControl(kubeClientset),
 		TiDBControl:        NewDefaultTiDBControl(kubeClientset),
 		BackupControl:      NewRealBackupControl(clientset, recorder),
 	}
@@ -268,6 +352,7 @@ func newDependencies(
 		StorageClassLister:          kubeInformerFactory.Storage().V1().StorageClasses().Lister(),
 		JobLister:                   kubeInformerFactory.Batch().V1().Jobs().Lister(),
 		IngressLister:               kubeInformerFactory.Extensions().V1beta1().Ingresses().Lister(),
+		PDBLister:                   kubeInformerFactory.Policy().V1beta1().PodDisruptionBudgets().Lister(),
 		TiDBClusterLister:           informerFactory.Pingcap().V1alpha1().TidbClusters().Lister(),
 		TiDBClusterAutoScalerLister: informerFactory.Pingcap().V1alpha1().TidbClusterAutoScalers().Lister(),
 		DMClusterLister:             informerFactory.Pingcap().V1alpha1().DMClusters().Lister(),
@@ -338,7 +423,8 @@ func newFakeControl(kubeClientset kubernetes.Interface, informerFactory informer
 		TiKVControl:        tikvapi.NewFakeTiKVControl(kubeClientset),
 		DMMasterControl:    dmapi.NewFakeMasterControl(kubeClientset),
 		TiDBClusterControl: NewFakeTidbClusterControl(informerFactory.Pingcap().V1alpha1().TidbClusters()),
-		CDCControl:         NewDefaultTiCDCControl(kubeClientset), // TODO: no fake control?
+		CDCControl:         NewFakeTiCDCControl(),
+		PumpControl:        NewFakePumpControl(),
 		TiDBControl:        NewFakeTiDBControl(),
 		BackupControl:      NewFakeBackupControl(informerFactory.Pingcap().V1alpha1().Backups()),
 	}
@@ -49,3 +49,33 @@ func TestFakeTidbCluster(t *testing.T) {
 		}, time.Second*10).Should(BeNil())
 	}
 }
+
+func TestControllerWorkers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg := DefaultCLIConfig()
+	w := (*controllerWorkers)(&cfg.ControllerWorkers)
+	g.Expect(w.Set("tidbcluster=10, backup=2,")).Should(Succeed())
+	g.Expect(w.String()).Should(Equal("backup=2,tidbcluster=10"))
+	g.Expect(cfg.WorkersOf("tidbcluster")).Should(Equal(10))
+	g.Expect(cfg.WorkersOf("backup")).Should(Equal(2))
+	g.Expect(cfg.WorkersOf("restore")).Should(Equal(cfg.Workers))
+
+	for _, invalid := range []string{"tidbcluster", "=1", "backup=0", "backup=x"} {
+		g.Expect(w.Set(invalid)).ShouldNot(Succeed(), invalid)
+	}
+}
+
+func TestCLIConfigValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg := DefaultCLIConfig()
+	g.Expect(cfg.Validate()).Should(Succeed())
+
+	cfg.RateLimiterQPS = 0
+	g.Expect(cfg.Validate()).ShouldNot(Succeed())
+
+	cfg = DefaultCLIConfig()
+	cfg.RateLimiterBurst = -1
+	g.Expect(cfg.Validate()).ShouldNot(Succeed())
+}
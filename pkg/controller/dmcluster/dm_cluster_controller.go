@@ -58,7 +58,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 			deps.Recorder,
 		),
 		queue: workqueue.NewNamedRateLimitingQueue(
-			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
+			controller.NewControllerRateLimiter(deps.CLIConfig.RateLimiterBaseDelay, deps.CLIConfig.RateLimiterMaxDelay, deps.CLIConfig.RateLimiterQPS, deps.CLIConfig.RateLimiterBurst),
 			"dmcluster",
 		),
 	}
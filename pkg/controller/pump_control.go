@@ -0,0 +1,181 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// PumpNodeOnline is the state of an online pump node
+	PumpNodeOnline = "online"
+	// PumpNodeClosing is the state of a pump node which is going offline
+	PumpNodeClosing = "closing"
+	// PumpNodeOffline is the state of an offline pump node
+	PumpNodeOffline = "offline"
+)
+
+// PumpNodeStatus is the status of a pump node registered in PD
+type PumpNodeStatus struct {
+	NodeID string `json:"nodeId"`
+	Host   string `json:"host"`
+	State  string `json:"state"`
+}
+
+type pumpStatus struct {
+	StatusMap map[string]*PumpNodeStatus `json:"StatusMap"`
+	ErrMsg    string                     `json:"ErrMsg"`
+}
+
+// PumpControlInterface is the interface that knows how to manage pump nodes
+type PumpControlInterface interface {
+	// GetNodesStatus returns the status of all pump nodes seen by the pump of the ordinal,
+	// the key of the map is the node ID
+	GetNodesStatus(tc *v1alpha1.TidbCluster, ordinal int32) (map[string]*PumpNodeStatus, error)
+	// OfflineNode asks the pump of the ordinal to go offline, the pump stops
+	// receiving binlogs and exits after all binlogs are consumed by drainers
+	OfflineNode(tc *v1alpha1.TidbCluster, ordinal int32) error
+}
+
+// defaultPumpControl is default implementation of PumpControlInterface.
+type defaultPumpControl struct {
+	httpClient
+	// for unit test only
+	testURL string
+}
+
+// NewDefaultPumpControl returns a defaultPumpControl instance
+func NewDefaultPumpControl(kubeCli kubernetes.Interface) *defaultPumpControl {
+	return &defaultPumpControl{httpClient: httpClient{kubeCli: kubeCli}}
+}
+
+func (c *defaultPumpControl) GetNodesStatus(tc *v1alpha1.TidbCluster, ordinal int32) (map[string]*PumpNodeStatus, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/status", c.getBaseURL(tc, ordinal))
+	body, err := getBodyOK(httpClient, url)
+	if err != nil {
+		return nil, err
+	}
+
+	status := pumpStatus{}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	if status.ErrMsg != "" {
+		return nil, fmt.Errorf("failed to get pump status, URL: %s, error: %s", url, status.ErrMsg)
+	}
+	return status.StatusMap, nil
+}
+
+func (c *defaultPumpControl) OfflineNode(tc *v1alpha1.TidbCluster, ordinal int32) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/state/%s/close", c.getBaseURL(tc, ordinal), PumpNodeID(tc.GetName(), ordinal))
+	req, err := http.NewRequest("PUT", url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, url)
+}
+
+func (c *defaultPumpControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+	hostName := fmt.Sprintf("%s-%d", PumpMemberName(tcName), ordinal)
+
+	return fmt.Sprintf("%s://%s.%s.%s:8250", scheme, hostName, PumpPeerMemberName(tcName), ns)
+}
+
+// PumpNodeID returns the node ID the pump of the ordinal registers in PD,
+// which is the hostname and the port of the pump
+func PumpNodeID(tcName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d:8250", PumpMemberName(tcName), ordinal)
+}
+
+// FakePumpControl is a fake implementation of PumpControlInterface.
+type FakePumpControl struct {
+	nodes      map[string]*PumpNodeStatus
+	statusErr  error
+	offlineErr error
+}
+
+// NewFakePumpControl returns a FakePumpControl instance
+func NewFakePumpControl() *FakePumpControl {
+	return &FakePumpControl{}
+}
+
+// SetNodeState sets the state of the pump node of the ordinal for FakePumpControl
+func (c *FakePumpControl) SetNodeState(tcName string, ordinal int32, state string) {
+	if c.nodes == nil {
+		c.nodes = map[string]*PumpNodeStatus{}
+	}
+	nodeID := PumpNodeID(tcName, ordinal)
+	c.nodes[nodeID] = &PumpNodeStatus{NodeID: nodeID, State: state}
+}
+
+// SetGetNodesStatusError sets the error returned by GetNodesStatus for FakePumpControl
+func (c *FakePumpControl) SetGetNodesStatusError(err error) {
+	c.statusErr = err
+}
+
+// SetOfflineNodeError sets the error returned by OfflineNode for FakePumpControl
+func (c *FakePumpControl) SetOfflineNodeError(err error) {
+	c.offlineErr = err
+}
+
+func (c *FakePumpControl) GetNodesStatus(tc *v1alpha1.TidbCluster, ordinal int32) (map[string]*PumpNodeStatus, error) {
+	if c.statusErr != nil {
+		return nil, c.statusErr
+	}
+	return c.nodes, nil
+}
+
+func (c *FakePumpControl) OfflineNode(tc *v1alpha1.TidbCluster, ordinal int32) error {
+	if c.offlineErr != nil {
+		return c.offlineErr
+	}
+	c.SetNodeState(tc.GetName(), ordinal, PumpNodeClosing)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPumpNodesStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName      string
+		status        pumpStatus
+		statusCode    int
+		expectedState string
+		expectedErr   bool
+	}{
+		{
+			caseName: "get nodes status",
+			status: pumpStatus{
+				StatusMap: map[string]*PumpNodeStatus{
+					"demo-pump-0:8250": {NodeID: "demo-pump-0:8250", State: PumpNodeOnline},
+				},
+			},
+			statusCode:    http.StatusOK,
+			expectedState: PumpNodeOnline,
+		},
+		{
+			caseName:    "error message in status",
+			status:      pumpStatus{ErrMsg: "get status failed"},
+			statusCode:  http.StatusOK,
+			expectedErr: true,
+		},
+		{
+			caseName:    "error response",
+			statusCode:  http.StatusInternalServerError,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.URL.Path).To(Equal("/status"), "check url")
+			w.WriteHeader(c.statusCode)
+			data, err := json.Marshal(c.status)
+			g.Expect(err).NotTo(HaveOccurred())
+			w.Write(data)
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultPumpControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		nodes, err := control.GetNodesStatus(tc, 0)
+		if c.expectedErr {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+			continue
+		}
+		g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		g.Expect(nodes["demo-pump-0:8250"].State).To(Equal(c.expectedState), c.caseName)
+	}
+}
+
+func TestOfflinePumpNode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName    string
+		statusCode  int
+		expectedErr bool
+	}{
+		{
+			caseName:   "offline node",
+			statusCode: http.StatusOK,
+		},
+		{
+			caseName:    "offline node failed",
+			statusCode:  http.StatusBadRequest,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("PUT"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/state/demo-pump-1:8250/close"), "check url")
+			w.WriteHeader(c.statusCode)
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultPumpControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		err := control.OfflineNode(tc, 1)
+		if c.expectedErr {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+	}
+}
@@ -47,7 +47,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 		deps:    deps,
 		control: NewDefaultRestoreControl(restore.NewRestoreManager(deps)),
 		queue: workqueue.NewNamedRateLimitingQueue(
-			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
+			controller.NewControllerRateLimiter(deps.CLIConfig.RateLimiterBaseDelay, deps.CLIConfig.RateLimiterMaxDelay, deps.CLIConfig.RateLimiterQPS, deps.CLIConfig.RateLimiterBurst),
 			"restore",
 		),
 	}
@@ -14,21 +14,46 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
 )
 
 type CaptureStatus struct {
-	ID string `json:"id"`
+	ID      string `json:"id"`
+	IsOwner bool   `json:"is_owner"`
+}
+
+type captureInfo struct {
+	ID      string `json:"id"`
+	IsOwner bool   `json:"is_owner"`
+}
+
+type drainCaptureRequest struct {
+	CaptureID string `json:"capture_id"`
+}
+
+type drainCaptureResp struct {
+	CurrentTableCount int `json:"current_table_count"`
 }
 
 // TiCDCControlInterface is the interface that knows how to manage ticdc captures
 type TiCDCControlInterface interface {
 	// GetStatus returns ticdc's status
 	GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*CaptureStatus, error)
+	// DrainCapture moves the tables replicated by the capture to other captures,
+	// it returns the count of tables remaining on the capture and whether to retry later.
+	DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (tableCount int, retry bool, err error)
+	// ResignOwner resigns the owner if the capture is the owner, it returns
+	// true if the capture is not the owner anymore.
+	ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (ok bool, err error)
 }
 
 // defaultTiCDCControl is default implementation of TiCDCControlInterface.
@@ -61,6 +86,138 @@ func (c *defaultTiCDCControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32)
 	return &status, err
 }
 
+func (c *defaultTiCDCControl) DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return 0, false, err
+	}
+
+	baseURL := c.getBaseURL(tc, ordinal)
+	this, captures, err := c.getCaptures(httpClient, baseURL)
+	if err != nil {
+		return 0, false, err
+	}
+	if this == nil {
+		// the capture has not registered yet or has gone
+		return 0, false, nil
+	}
+	if len(captures) <= 1 {
+		// no other capture can take over the tables
+		return 0, false, nil
+	}
+	if this.IsOwner {
+		// owner can not be drained, it should be resigned first
+		return 0, true, nil
+	}
+
+	data, err := json.Marshal(drainCaptureRequest{CaptureID: this.ID})
+	if err != nil {
+		return 0, false, err
+	}
+	url := fmt.Sprintf("%s/api/v1/captures/drain", baseURL)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+	case http.StatusNotFound:
+		// drain capture is not supported by the TiCDC version
+		klog.Infof("ticdc: [%s/%s] drain capture of ordinal %d is not supported", tc.GetNamespace(), tc.GetName(), ordinal)
+		return 0, false, nil
+	case http.StatusServiceUnavailable:
+		// the owner is not ready to drain captures
+		return 0, true, nil
+	default:
+		return 0, false, fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, url)
+	}
+
+	resp := drainCaptureResp{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, false, err
+	}
+	return resp.CurrentTableCount, false, nil
+}
+
+func (c *defaultTiCDCControl) ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return false, err
+	}
+
+	baseURL := c.getBaseURL(tc, ordinal)
+	this, captures, err := c.getCaptures(httpClient, baseURL)
+	if err != nil {
+		return false, err
+	}
+	if this == nil || !this.IsOwner {
+		return true, nil
+	}
+	if len(captures) <= 1 {
+		// no other capture can become the owner
+		return true, nil
+	}
+
+	url := fmt.Sprintf("%s/api/v1/owner/resign", baseURL)
+	res, err := httpClient.Post(url, "application/json", nil)
+	if err != nil {
+		return false, err
+	}
+	defer httputil.DeferClose(res.Body)
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		// the owner is resigned, check again after the new owner is elected
+		return false, nil
+	case http.StatusNotFound:
+		// resign owner is not supported by the TiCDC version
+		return true, nil
+	default:
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, url)
+	}
+}
+
+// getCaptures returns the capture served at baseURL and all captures of the cluster
+func (c *defaultTiCDCControl) getCaptures(httpClient *http.Client, baseURL string) (*captureInfo, []captureInfo, error) {
+	body, err := getBodyOK(httpClient, fmt.Sprintf("%s/status", baseURL))
+	if err != nil {
+		return nil, nil, err
+	}
+	status := CaptureStatus{}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, nil, err
+	}
+
+	body, err = getBodyOK(httpClient, fmt.Sprintf("%s/api/v1/captures", baseURL))
+	if err != nil {
+		return nil, nil, err
+	}
+	var captures []captureInfo
+	if err := json.Unmarshal(body, &captures); err != nil {
+		return nil, nil, err
+	}
+	for i := range captures {
+		if captures[i].ID == status.ID {
+			return &captures[i], captures, nil
+		}
+	}
+	return nil, captures, nil
+}
+
 func (c *defaultTiCDCControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
 	if c.testURL != "" {
 		return c.testURL
@@ -76,7 +233,11 @@ func (c *defaultTiCDCControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32
 
 // FakeTiCDCControl is a fake implementation of TiCDCControlInterface.
 type FakeTiCDCControl struct {
-	status *CaptureStatus
+	status     *CaptureStatus
+	tableCount map[string]int
+	drainErr   error
+	owner      string
+	resignErr  error
 }
 
 // NewFakeTiCDCControl returns a FakeTiCDCControl instance
@@ -88,3 +249,51 @@ func NewFakeTiCDCControl() *FakeTiCDCControl {
 func (c *FakeTiCDCControl) SetStatus(status *CaptureStatus) {
 	c.status = status
 }
+
+// SetTableCount sets the count of tables remaining on the captures for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetTableCount(tableCount map[string]int) {
+	c.tableCount = tableCount
+}
+
+// SetDrainCaptureError sets the error returned by DrainCapture for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetDrainCaptureError(err error) {
+	c.drainErr = err
+}
+
+// SetOwner sets the pod name of the owner for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetOwner(podName string) {
+	c.owner = podName
+}
+
+// SetResignOwnerError sets the error returned by ResignOwner for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetResignOwnerError(err error) {
+	c.resignErr = err
+}
+
+func (c *FakeTiCDCControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*CaptureStatus, error) {
+	if c.status == nil {
+		return &CaptureStatus{}, nil
+	}
+	return c.status, nil
+}
+
+func (c *FakeTiCDCControl) DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+	if c.drainErr != nil {
+		return 0, false, c.drainErr
+	}
+	podName := fmt.Sprintf("%s-%d", TiCDCMemberName(tc.GetName()), ordinal)
+	return c.tableCount[podName], false, nil
+}
+
+func (c *FakeTiCDCControl) ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	if c.resignErr != nil {
+		return false, c.resignErr
+	}
+	podName := fmt.Sprintf("%s-%d", TiCDCMemberName(tc.GetName()), ordinal)
+	if c.owner == podName {
+		// the owner is resigned, another capture becomes the owner
+		c.owner = ""
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDrainCapture(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName      string
+		status        CaptureStatus
+		captures      []captureInfo
+		drainStatus   int
+		drainResp     drainCaptureResp
+		expectDrain   bool
+		expectedCount int
+		expectedRetry bool
+		expectedErr   bool
+	}{
+		{
+			caseName: "drain capture",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1"},
+				{ID: "2", IsOwner: true},
+			},
+			drainStatus:   http.StatusAccepted,
+			drainResp:     drainCaptureResp{CurrentTableCount: 3},
+			expectDrain:   true,
+			expectedCount: 3,
+		},
+		{
+			caseName: "only one capture",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1", IsOwner: true},
+			},
+		},
+		{
+			caseName: "capture is the owner",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1", IsOwner: true},
+				{ID: "2"},
+			},
+			expectedRetry: true,
+		},
+		{
+			caseName: "drain capture is not supported",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1"},
+				{ID: "2", IsOwner: true},
+			},
+			drainStatus: http.StatusNotFound,
+			expectDrain: true,
+		},
+		{
+			caseName: "owner is not ready",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1"},
+				{ID: "2", IsOwner: true},
+			},
+			drainStatus:   http.StatusServiceUnavailable,
+			expectDrain:   true,
+			expectedRetry: true,
+		},
+		{
+			caseName: "drain capture failed",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1"},
+				{ID: "2", IsOwner: true},
+			},
+			drainStatus: http.StatusInternalServerError,
+			expectDrain: true,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		drained := false
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			switch request.URL.Path {
+			case "/status":
+				data, err := json.Marshal(c.status)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			case "/api/v1/captures":
+				data, err := json.Marshal(c.captures)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			case "/api/v1/captures/drain":
+				g.Expect(request.Method).To(Equal("PUT"), "check method")
+				body, err := ioutil.ReadAll(request.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				req := drainCaptureRequest{}
+				g.Expect(json.Unmarshal(body, &req)).To(Succeed())
+				g.Expect(req.CaptureID).To(Equal(c.status.ID))
+				drained = true
+				w.WriteHeader(c.drainStatus)
+				data, err := json.Marshal(c.drainResp)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiCDCControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		count, retry, err := control.DrainCapture(tc, 0)
+		if c.expectedErr {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+		g.Expect(count).To(Equal(c.expectedCount), c.caseName)
+		g.Expect(retry).To(Equal(c.expectedRetry), c.caseName)
+		g.Expect(drained).To(Equal(c.expectDrain), c.caseName)
+	}
+}
+
+func TestResignOwner(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName     string
+		status       CaptureStatus
+		captures     []captureInfo
+		resignStatus int
+		expectResign bool
+		expectedOK   bool
+		expectedErr  bool
+	}{
+		{
+			caseName: "capture is not the owner",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1"},
+				{ID: "2", IsOwner: true},
+			},
+			expectedOK: true,
+		},
+		{
+			caseName: "only one capture",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1", IsOwner: true},
+			},
+			expectedOK: true,
+		},
+		{
+			caseName: "resign owner",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1", IsOwner: true},
+				{ID: "2"},
+			},
+			resignStatus: http.StatusAccepted,
+			expectResign: true,
+			expectedOK:   false,
+		},
+		{
+			caseName: "resign owner failed",
+			status:   CaptureStatus{ID: "1"},
+			captures: []captureInfo{
+				{ID: "1", IsOwner: true},
+				{ID: "2"},
+			},
+			resignStatus: http.StatusInternalServerError,
+			expectResign: true,
+			expectedErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		resigned := false
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			switch request.URL.Path {
+			case "/status":
+				data, err := json.Marshal(c.status)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			case "/api/v1/captures":
+				data, err := json.Marshal(c.captures)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			case "/api/v1/owner/resign":
+				g.Expect(request.Method).To(Equal("POST"), "check method")
+				resigned = true
+				w.WriteHeader(c.resignStatus)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiCDCControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		ok, err := control.ResignOwner(tc, 0)
+		if c.expectedErr {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+		g.Expect(ok).To(Equal(c.expectedOK), c.caseName)
+		g.Expect(resigned).To(Equal(c.expectResign), c.caseName)
+	}
+}
@@ -14,15 +14,26 @@
 package controller
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"github.com/pingcap/tidb/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -31,10 +42,13 @@ const (
 	// NotDDLOwnerError is the error message which was returned when the tidb node is not a ddl owner
 	NotDDLOwnerError = "This node is not a ddl owner, can't be resigned."
 	timeout          = 5 * time.Second
+	// sqlCheckTimeout is kept short as the SQL health check runs in the sync loop of the cluster
+	sqlCheckTimeout = 2 * time.Second
 )
 
 type DBInfo struct {
-	IsOwner bool `json:"is_owner"`
+	IsOwner bool              `json:"is_owner"`
+	Labels  map[string]string `json:"labels,omitempty"`
 }
 
 // TiDBControlInterface is the interface that knows how to manage tidb peers
@@ -43,6 +57,15 @@ type TiDBControlInterface interface {
 	GetHealth(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error)
 	// Get TIDB info return tidb's DBInfo
 	GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*DBInfo, error)
+	// ResignDDLOwner resigns the ddl owner of tidb, returns whether the tidb node was the ddl owner
+	ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error)
+	// SetServerLabels sets the server labels of tidb
+	SetServerLabels(tc *v1alpha1.TidbCluster, ordinal int32, labels map[string]string) error
+	// CheckSQL executes `SELECT 1` against tidb with the given user and password
+	CheckSQL(tc *v1alpha1.TidbCluster, ordinal int32, user, password string) error
+	// ReloadTLS reloads the certificate of the MySQL protocol by `ALTER INSTANCE RELOAD TLS` with the given
+	// user and password, it returns whether the certificate served by tidb is the expected cert (in PEM) after reloading
+	ReloadTLS(tc *v1alpha1.TidbCluster, ordinal int32, user, password string, cert []byte) (bool, error)
 	// GetSettings return the TiDB instance settings
 	GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error)
 }
@@ -104,6 +127,150 @@ func (c *defaultTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*
 	return &info, nil
 }
 
+func (c *defaultTiDBControl) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return false, err
+	}
+
+	baseURL := c.getBaseURL(tc, ordinal)
+	url := fmt.Sprintf("%s/ddl/owner/resign", baseURL)
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return false, err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	if res.StatusCode == http.StatusOK {
+		return true, nil
+	}
+	if strings.Contains(string(body), NotDDLOwnerError) {
+		return false, nil
+	}
+	return false, fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, url)
+}
+
+func (c *defaultTiDBControl) SetServerLabels(tc *v1alpha1.TidbCluster, ordinal int32, labels map[string]string) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	baseURL := c.getBaseURL(tc, ordinal)
+	url := fmt.Sprintf("%s/labels", baseURL)
+	res, err := httpClient.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, url)
+	}
+	return nil
+}
+
+func (c *defaultTiDBControl) CheckSQL(tc *v1alpha1.TidbCluster, ordinal int32, user, password string) error {
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = c.getSQLAddr(tc, ordinal)
+	cfg.Timeout = sqlCheckTimeout
+	cfg.ReadTimeout = sqlCheckTimeout
+	cfg.WriteTimeout = sqlCheckTimeout
+	if tc.Spec.TiDB.IsTLSClientEnabled() {
+		tlsConfig, err := c.getSQLTLSConfig(tc, ordinal)
+		if err != nil {
+			return err
+		}
+		tlsName := fmt.Sprintf("check-sql-%s-%s-%d", tc.GetNamespace(), tc.GetName(), ordinal)
+		if err := mysql.RegisterTLSConfig(tlsName, tlsConfig); err != nil {
+			return err
+		}
+		defer mysql.DeregisterTLSConfig(tlsName)
+		cfg.TLSConfig = tlsName
+	}
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqlCheckTimeout)
+	defer cancel()
+	var v int
+	return db.QueryRowContext(ctx, "SELECT 1").Scan(&v)
+}
+
+func (c *defaultTiDBControl) ReloadTLS(tc *v1alpha1.TidbCluster, ordinal int32, user, password string, cert []byte) (bool, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return false, fmt.Errorf("failed to decode the certificate of tidb %s", fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal))
+	}
+
+	// the server certificate is captured instead of verified, to check whether the expected one is served
+	var served []byte
+	tlsName := fmt.Sprintf("reload-tls-%s-%s-%d", tc.GetNamespace(), tc.GetName(), ordinal)
+	err := mysql.RegisterTLSConfig(tlsName, &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) > 0 {
+				served = rawCerts[0]
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	defer mysql.DeregisterTLSConfig(tlsName)
+
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = c.getSQLAddr(tc, ordinal)
+	cfg.Timeout = timeout
+	cfg.ReadTimeout = timeout
+	cfg.TLSConfig = tlsName
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+	// connections are not reused, so that the certificate is checked with a new TLS handshake
+	db.SetMaxIdleConns(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, "ALTER INSTANCE RELOAD TLS"); err != nil {
+		return false, err
+	}
+	served = nil
+	var v int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&v); err != nil {
+		return false, err
+	}
+	return bytes.Equal(served, block.Bytes), nil
+}
+
 func (c *defaultTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	httpClient, err := c.getHTTPClient(tc)
 	if err != nil {
@@ -168,12 +335,64 @@ func (c *defaultTiDBControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32)
 	return fmt.Sprintf("%s://%s.%s.%s:10080", scheme, hostName, TiDBPeerMemberName(tcName), ns)
 }
 
+func (c *defaultTiDBControl) getSQLHost(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	tcName := tc.GetName()
+	hostName := fmt.Sprintf("%s-%d", TiDBMemberName(tcName), ordinal)
+
+	return fmt.Sprintf("%s.%s.%s", hostName, TiDBPeerMemberName(tcName), tc.GetNamespace())
+}
+
+func (c *defaultTiDBControl) getSQLAddr(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	return fmt.Sprintf("%s:4000", c.getSQLHost(tc, ordinal))
+}
+
+// getSQLTLSConfig returns the TLS config to connect the MySQL protocol of tidb, the server
+// certificate is verified with the CA in the tidb client secret, and the client certificate
+// in the secret is presented if it exists.
+func (c *defaultTiDBControl) getSQLTLSConfig(tc *v1alpha1.TidbCluster, ordinal int32) (*tls.Config, error) {
+	ns := tc.GetNamespace()
+	secretName := util.TiDBClientTLSSecretName(tc.GetName())
+	secret, err := c.kubeCli.CoreV1().Secrets(ns).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(secret.Data[corev1.ServiceAccountRootCAKey]) {
+		return nil, fmt.Errorf("failed to load the CA from secret %s/%s", ns, secretName)
+	}
+	config := &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: c.getSQLHost(tc, ordinal),
+	}
+	clientCert, certExists := secret.Data[corev1.TLSCertKey]
+	clientKey, keyExists := secret.Data[corev1.TLSPrivateKeyKey]
+	if certExists && keyExists {
+		tlsCert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load certificates from secret %s/%s: %v", ns, secretName, err)
+		}
+		config.Certificates = []tls.Certificate{tlsCert}
+	}
+	return config, nil
+}
+
 // FakeTiDBControl is a fake implementation of TiDBControlInterface.
 type FakeTiDBControl struct {
 	healthInfo   map[string]bool
 	tiDBInfo     *DBInfo
 	getInfoError error
 	tidbConfig   *config.Config
+	// ddlOwner is the pod name of the tidb which is the ddl owner
+	ddlOwner          string
+	resignDDLOwnerErr error
+	serverLabels      map[string]map[string]string
+	setLabelsErr      error
+	sqlReady          map[string]bool
+	reloadTLSErr      error
+	// tlsCertPending is the pod names of the tidb which have not mounted the rotated certificate
+	tlsCertPending map[string]bool
+	tlsReloaded    map[string]bool
 }
 
 // NewFakeTiDBControl returns a FakeTiDBControl instance
@@ -201,6 +420,86 @@ func (c *FakeTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*DBI
 	return c.tiDBInfo, c.getInfoError
 }
 
+// SetServerLabelsError sets the error returned by SetServerLabels for FakeTiDBControl
+func (c *FakeTiDBControl) SetServerLabelsError(err error) {
+	c.setLabelsErr = err
+}
+
+// GetServerLabels returns the server labels set by SetServerLabels
+func (c *FakeTiDBControl) GetServerLabels(podName string) map[string]string {
+	return c.serverLabels[podName]
+}
+
+func (c *FakeTiDBControl) SetServerLabels(tc *v1alpha1.TidbCluster, ordinal int32, labels map[string]string) error {
+	if c.setLabelsErr != nil {
+		return c.setLabelsErr
+	}
+	if c.serverLabels == nil {
+		c.serverLabels = map[string]map[string]string{}
+	}
+	podName := fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal)
+	c.serverLabels[podName] = labels
+	return nil
+}
+
+// SetDDLOwner sets the pod name of the ddl owner for FakeTiDBControl
+func (c *FakeTiDBControl) SetDDLOwner(podName string) {
+	c.ddlOwner = podName
+}
+
+// SetResignDDLOwnerError sets the error returned by ResignDDLOwner for FakeTiDBControl
+func (c *FakeTiDBControl) SetResignDDLOwnerError(err error) {
+	c.resignDDLOwnerErr = err
+}
+
+func (c *FakeTiDBControl) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	if c.resignDDLOwnerErr != nil {
+		return false, c.resignDDLOwnerErr
+	}
+	podName := fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal)
+	return c.ddlOwner == podName, nil
+}
+
+// SetSQLReady sets the result of CheckSQL for FakeTiDBControl
+func (c *FakeTiDBControl) SetSQLReady(sqlReady map[string]bool) {
+	c.sqlReady = sqlReady
+}
+
+func (c *FakeTiDBControl) CheckSQL(tc *v1alpha1.TidbCluster, ordinal int32, user, password string) error {
+	podName := fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal)
+	if !c.sqlReady[podName] {
+		return fmt.Errorf("tidb %s can not execute SQL", podName)
+	}
+	return nil
+}
+
+// SetReloadTLSError sets the error returned by ReloadTLS for FakeTiDBControl
+func (c *FakeTiDBControl) SetReloadTLSError(err error) {
+	c.reloadTLSErr = err
+}
+
+// SetTLSCertPending sets the tidb pods which serve the old certificate after reloading for FakeTiDBControl
+func (c *FakeTiDBControl) SetTLSCertPending(pending map[string]bool) {
+	c.tlsCertPending = pending
+}
+
+// TLSReloaded returns whether ReloadTLS is called for the tidb pod
+func (c *FakeTiDBControl) TLSReloaded(podName string) bool {
+	return c.tlsReloaded[podName]
+}
+
+func (c *FakeTiDBControl) ReloadTLS(tc *v1alpha1.TidbCluster, ordinal int32, user, password string, cert []byte) (bool, error) {
+	if c.reloadTLSErr != nil {
+		return false, c.reloadTLSErr
+	}
+	podName := fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal)
+	if c.tlsReloaded == nil {
+		c.tlsReloaded = map[string]bool{}
+	}
+	c.tlsReloaded[podName] = true
+	return !c.tlsCertPending[podName], nil
+}
+
 func (c *FakeTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	return c.tidbConfig, c.getInfoError
 }
@@ -208,6 +208,60 @@ func TestInfo(t *testing.T) {
 	}
 }
 
+func TestResignDDLOwner(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName    string
+		status      int
+		resp        string
+		expected    bool
+		errExpected bool
+	}{
+		{
+			caseName: "ResignDDLOwner is owner",
+			status:   http.StatusOK,
+			resp:     "\"success!\"",
+			expected: true,
+		},
+		{
+			caseName: "ResignDDLOwner is not owner",
+			status:   http.StatusBadRequest,
+			resp:     NotDDLOwnerError,
+			expected: false,
+		},
+		{
+			caseName:    "ResignDDLOwner failed",
+			status:      http.StatusInternalServerError,
+			resp:        "internal error",
+			errExpected: true,
+		},
+	}
+
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/ddl/owner/resign"), "check url")
+
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.resp))
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiDBControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		result, err := control.ResignDDLOwner(tc, 0)
+		if c.errExpected {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+			g.Expect(result).To(Equal(c.expected), c.caseName)
+		}
+	}
+}
+
 func TestSettings(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -14,6 +14,11 @@
 package tidbcluster
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	appsv1 "k8s.io/api/apps/v1"
@@ -26,6 +31,10 @@ type TidbClusterConditionUpdater interface {
 	Update(*v1alpha1.TidbCluster) error
 }
 
+// tikvWarmUpTimeout is how long the TiKVWarmedUp condition stays False after
+// TiKV is scaled if the stores are not balanced.
+const tikvWarmUpTimeout = 30 * time.Minute
+
 type tidbClusterConditionUpdater struct {
 }
 
@@ -33,6 +42,13 @@ var _ TidbClusterConditionUpdater = &tidbClusterConditionUpdater{}
 
 func (u *tidbClusterConditionUpdater) Update(tc *v1alpha1.TidbCluster) error {
 	u.updateReadyCondition(tc)
+	u.updateTiKVSlowStoreCondition(tc)
+	u.updateTiKVDiskUsageCondition(tc)
+	u.updateTiDBSQLReadyCondition(tc)
+	u.updateTiKVWarmedUpCondition(tc)
+	u.updateProgressingCondition(tc)
+	u.updateSuspendedCondition(tc)
+	u.updateDegradedCondition(tc)
 	// in the future, we may return error when we need to Kubernetes API, etc.
 	return nil
 }
@@ -50,6 +66,14 @@ func allStatefulSetsAreUpToDate(tc *v1alpha1.TidbCluster) bool {
 		isUpToDate(tc.Status.TiFlash.StatefulSet, false)
 }
 
+// setCondition sets the condition with the observed generation of the tidb cluster,
+// so that clients can tell whether the condition reflects the latest spec.
+func setCondition(tc *v1alpha1.TidbCluster, condType v1alpha1.TidbClusterConditionType, status v1.ConditionStatus, reason, message string) {
+	cond := utiltidbcluster.NewTidbClusterCondition(condType, status, reason, message)
+	cond.ObservedGeneration = tc.Generation
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
 func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbCluster) {
 	status := v1.ConditionFalse
 	reason := ""
@@ -65,10 +89,10 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 	case tc.Spec.TiKV != nil && !tc.TiKVAllStoresReady():
 		reason = utiltidbcluster.TiKVStoreNotUp
 		message = "TiKV store(s) are not up"
-	case tc.Spec.TiDB != nil && !tc.TiDBAllMembersReady():
+	case tc.Spec.TiDB != nil && !tc.TiDBSuspended() && !tc.TiDBAllMembersReady():
 		reason = utiltidbcluster.TiDBUnhealthy
 		message = "TiDB(s) are not healthy"
-	case !tc.TiFlashAllStoresReady():
+	case !tc.TiFlashSuspended() && !tc.TiFlashAllStoresReady():
 		reason = utiltidbcluster.TiFlashStoreNotUp
 		message = "TiFlash store(s) are not up"
 	default:
@@ -76,6 +100,190 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 		reason = utiltidbcluster.Ready
 		message = "TiDB cluster is fully up and running"
 	}
-	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterReady, status, reason, message)
-	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	setCondition(tc, v1alpha1.TidbClusterReady, status, reason, message)
+}
+
+func (u *tidbClusterConditionUpdater) updateTiKVSlowStoreCondition(tc *v1alpha1.TidbCluster) {
+	if !tc.TiKVSlowStoreProtectionEnabled() {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterTiKVSlowStore)
+		return
+	}
+
+	status := v1.ConditionFalse
+	reason := utiltidbcluster.TiKVNoSlowStore
+	message := "No TiKV store is slow"
+	if slowStores := tc.TiKVSlowStores(); len(slowStores) > 0 {
+		podNames := make([]string, 0, len(slowStores))
+		for _, store := range slowStores {
+			podNames = append(podNames, store.PodName)
+		}
+		status = v1.ConditionTrue
+		reason = utiltidbcluster.TiKVSlowStoreDetected
+		message = fmt.Sprintf("TiKV store(s) of %s are slow", strings.Join(podNames, ","))
+	}
+	setCondition(tc, v1alpha1.TidbClusterTiKVSlowStore, status, reason, message)
+}
+
+func (u *tidbClusterConditionUpdater) updateTiKVDiskUsageCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.UpgradeDiskUsageThreshold == nil {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterTiKVDiskUsageHigh)
+		return
+	}
+
+	status := v1.ConditionFalse
+	reason := utiltidbcluster.TiKVStoreDiskUsageNormal
+	message := "Disk usage of all TiKV stores is below the threshold"
+	if stores := tc.TiKVHighDiskUsageStores(); len(stores) > 0 {
+		podNames := make([]string, 0, len(stores))
+		for _, store := range stores {
+			podNames = append(podNames, store.PodName)
+		}
+		status = v1.ConditionTrue
+		reason = utiltidbcluster.TiKVStoreDiskUsageHigh
+		message = fmt.Sprintf("Disk usage of TiKV store(s) of %s exceeds %d%%, upgrade is blocked", strings.Join(podNames, ","), *tc.Spec.TiKV.UpgradeDiskUsageThreshold)
+	}
+	setCondition(tc, v1alpha1.TidbClusterTiKVDiskUsageHigh, status, reason, message)
+}
+
+func (u *tidbClusterConditionUpdater) updateTiDBSQLReadyCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.TiDB == nil || tc.Spec.TiDB.SQLHealthCheckSecret == nil {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterSQLReady)
+		return
+	}
+
+	status := v1.ConditionTrue
+	reason := utiltidbcluster.TiDBSQLReady
+	message := "All TiDB instances can execute SQL"
+	var podNames []string
+	for _, member := range tc.Status.TiDB.Members {
+		if !member.SQLReady {
+			podNames = append(podNames, member.Name)
+		}
+	}
+	switch {
+	case len(tc.Status.TiDB.Members) == 0:
+		status = v1.ConditionFalse
+		reason = utiltidbcluster.TiDBSQLNotReady
+		message = "No TiDB instance is available"
+	case len(podNames) > 0:
+		sort.Strings(podNames)
+		status = v1.ConditionFalse
+		reason = utiltidbcluster.TiDBSQLNotReady
+		message = fmt.Sprintf("TiDB instance(s) of %s can not execute SQL", strings.Join(podNames, ","))
+	}
+	setCondition(tc, v1alpha1.TidbClusterSQLReady, status, reason, message)
+}
+
+// updateTiKVWarmedUpCondition sets the TiKVWarmedUp condition to False when TiKV is scaled,
+// and back to True once the stores are balanced or tikvWarmUpTimeout has passed since then.
+func (u *tidbClusterConditionUpdater) updateTiKVWarmedUpCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.WarmUpRegionPercent == nil {
+		utiltidbcluster.RemoveTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterTiKVWarmedUp)
+		return
+	}
+
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterTiKVWarmedUp)
+	switch {
+	case tc.Status.TiKV.Phase == v1alpha1.ScalePhase:
+		setCondition(tc, v1alpha1.TidbClusterTiKVWarmedUp, v1.ConditionFalse, utiltidbcluster.TiKVStoresWarmingUp, "TiKV is being scaled")
+	case cond != nil && cond.Status == v1.ConditionTrue:
+		setCondition(tc, v1alpha1.TidbClusterTiKVWarmedUp, v1.ConditionTrue, cond.Reason, cond.Message)
+	case tc.TiKVStoresWarmedUp():
+		setCondition(tc, v1alpha1.TidbClusterTiKVWarmedUp, v1.ConditionTrue, utiltidbcluster.TiKVStoresWarmedUp, "TiKV stores are balanced")
+	case cond != nil && time.Since(cond.LastTransitionTime.Time) > tikvWarmUpTimeout:
+		setCondition(tc, v1alpha1.TidbClusterTiKVWarmedUp, v1.ConditionTrue, utiltidbcluster.TiKVWarmUpTimeout,
+			fmt.Sprintf("TiKV stores are not balanced in %v after scaling", tikvWarmUpTimeout))
+	default:
+		setCondition(tc, v1alpha1.TidbClusterTiKVWarmedUp, v1.ConditionFalse, utiltidbcluster.TiKVStoresWarmingUp,
+			fmt.Sprintf("Region count of some TiKV stores is below %d%% of the average", *tc.Spec.TiKV.WarmUpRegionPercent))
+	}
+}
+
+func (u *tidbClusterConditionUpdater) updateProgressingCondition(tc *v1alpha1.TidbCluster) {
+	var upgrading, scaling []string
+	for _, c := range []struct {
+		name  string
+		phase v1alpha1.MemberPhase
+	}{
+		{"PD", tc.Status.PD.Phase},
+		{"TiKV", tc.Status.TiKV.Phase},
+		{"TiFlash", tc.Status.TiFlash.Phase},
+		{"TiDB", tc.Status.TiDB.Phase},
+		{"TiCDC", tc.Status.TiCDC.Phase},
+		{"Pump", tc.Status.Pump.Phase},
+	} {
+		switch c.phase {
+		case v1alpha1.UpgradePhase:
+			upgrading = append(upgrading, c.name)
+		case v1alpha1.ScalePhase:
+			scaling = append(scaling, c.name)
+		}
+	}
+
+	status := v1.ConditionTrue
+	reason := ""
+	message := ""
+	switch {
+	case len(upgrading) > 0:
+		reason = utiltidbcluster.ComponentsUpgrading
+		message = fmt.Sprintf("%s are being upgraded", strings.Join(upgrading, ","))
+	case len(scaling) > 0:
+		reason = utiltidbcluster.ComponentsScaling
+		message = fmt.Sprintf("%s are being scaled", strings.Join(scaling, ","))
+	case !allStatefulSetsAreUpToDate(tc):
+		reason = utiltidbcluster.StatfulSetNotUpToDate
+		message = "Statefulset(s) are in progress"
+	default:
+		status = v1.ConditionFalse
+		reason = utiltidbcluster.ComponentsUpToDate
+		message = "All components are up to date"
+	}
+	setCondition(tc, v1alpha1.TidbClusterProgressing, status, reason, message)
+}
+
+func (u *tidbClusterConditionUpdater) updateSuspendedCondition(tc *v1alpha1.TidbCluster) {
+	status := v1.ConditionFalse
+	reason := utiltidbcluster.NotPaused
+	message := "TiDB cluster is not paused"
+	if tc.Spec.Paused {
+		status = v1.ConditionTrue
+		reason = utiltidbcluster.Paused
+		message = "TiDB cluster is paused by spec.paused"
+	} else if components := tc.SuspendedComponents(); len(components) > 0 {
+		names := make([]string, 0, len(components))
+		for _, c := range components {
+			names = append(names, c.String())
+		}
+		status = v1.ConditionTrue
+		reason = utiltidbcluster.ComponentsSuspended
+		message = fmt.Sprintf("%s are suspended", strings.Join(names, ","))
+	}
+	setCondition(tc, v1alpha1.TidbClusterSuspended, status, reason, message)
+}
+
+func (u *tidbClusterConditionUpdater) updateDegradedCondition(tc *v1alpha1.TidbCluster) {
+	var failed []string
+	for _, c := range []struct {
+		name     string
+		failures int
+	}{
+		{"PD", len(tc.Status.PD.FailureMembers)},
+		{"TiKV", len(tc.Status.TiKV.FailureStores)},
+		{"TiFlash", len(tc.Status.TiFlash.FailureStores)},
+		{"TiDB", len(tc.Status.TiDB.FailureMembers)},
+	} {
+		if c.failures > 0 {
+			failed = append(failed, fmt.Sprintf("%d %s", c.failures, c.name))
+		}
+	}
+
+	status := v1.ConditionFalse
+	reason := utiltidbcluster.NoFailure
+	message := "No member has failed"
+	if len(failed) > 0 {
+		status = v1.ConditionTrue
+		reason = utiltidbcluster.FailoverInProgress
+		message = fmt.Sprintf("Failover is in progress for %s member(s)", strings.Join(failed, ","))
+	}
+	setCondition(tc, v1alpha1.TidbClusterDegraded, status, reason, message)
 }
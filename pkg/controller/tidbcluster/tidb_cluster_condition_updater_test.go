@@ -15,12 +15,15 @@ package tidbcluster
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestTidbClusterConditionUpdater_Ready(t *testing.T) {
@@ -360,3 +363,533 @@ func TestTidbClusterConditionUpdater_Ready(t *testing.T) {
 		})
 	}
 }
+
+func TestTidbClusterConditionUpdater_TiKVSlowStore(t *testing.T) {
+	tests := []struct {
+		name                string
+		slowStoreProtection bool
+		stores              map[string]v1alpha1.TiKVStore
+		wantCondition       bool
+		wantStatus          v1.ConditionStatus
+		wantReason          string
+		wantMessage         string
+	}{
+		{
+			name:                "slow store protection is disabled",
+			slowStoreProtection: false,
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, SlowScore: 100},
+			},
+			wantCondition: false,
+		},
+		{
+			name:                "no slow store",
+			slowStoreProtection: true,
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, SlowScore: 1},
+				"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateDown, SlowScore: 100},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiKVNoSlowStore,
+			wantMessage:   "No TiKV store is slow",
+		},
+		{
+			name:                "slow stores detected",
+			slowStoreProtection: true,
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, SlowScore: 100},
+				"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, SlowScore: 1},
+				"3": {ID: "3", PodName: "test-tikv-2", State: v1alpha1.TiKVStateUp, SlowScore: 100},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiKVSlowStoreDetected,
+			wantMessage:   "TiKV store(s) of test-tikv-0,test-tikv-2 are slow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: &v1alpha1.TiKVSpec{
+						SlowStoreProtection: &tt.slowStoreProtection,
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					TiKV: v1alpha1.TiKVStatus{
+						Stores: tt.stores,
+					},
+				},
+			}
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterTiKVSlowStore)
+			if !tt.wantCondition {
+				if cond != nil {
+					t.Errorf("unexpected condition: %v", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatalf("condition %s not found", v1alpha1.TidbClusterTiKVSlowStore)
+			}
+			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReason, cond.Reason); diff != "" {
+				t.Errorf("unexpected reason (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMessage, cond.Message); diff != "" {
+				t.Errorf("unexpected message (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_TiKVDiskUsage(t *testing.T) {
+	tests := []struct {
+		name          string
+		threshold     *int32
+		stores        map[string]v1alpha1.TiKVStore
+		wantCondition bool
+		wantStatus    v1.ConditionStatus
+		wantReason    string
+		wantMessage   string
+	}{
+		{
+			name:      "threshold is not set",
+			threshold: nil,
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, DiskUsagePercent: 95},
+			},
+			wantCondition: false,
+		},
+		{
+			name:      "disk usage is below the threshold",
+			threshold: pointer.Int32Ptr(80),
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, DiskUsagePercent: 80},
+				"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateDown, DiskUsagePercent: 95},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiKVStoreDiskUsageNormal,
+			wantMessage:   "Disk usage of all TiKV stores is below the threshold",
+		},
+		{
+			name:      "disk usage exceeds the threshold",
+			threshold: pointer.Int32Ptr(80),
+			stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, DiskUsagePercent: 81},
+				"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, DiskUsagePercent: 50},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiKVStoreDiskUsageHigh,
+			wantMessage:   "Disk usage of TiKV store(s) of test-tikv-0 exceeds 80%, upgrade is blocked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: &v1alpha1.TiKVSpec{
+						UpgradeDiskUsageThreshold: tt.threshold,
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					TiKV: v1alpha1.TiKVStatus{
+						Stores: tt.stores,
+					},
+				},
+			}
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterTiKVDiskUsageHigh)
+			if !tt.wantCondition {
+				if cond != nil {
+					t.Errorf("unexpected condition: %v", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatalf("condition %s not found", v1alpha1.TidbClusterTiKVDiskUsageHigh)
+			}
+			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReason, cond.Reason); diff != "" {
+				t.Errorf("unexpected reason (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMessage, cond.Message); diff != "" {
+				t.Errorf("unexpected message (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_TiKVWarmedUp(t *testing.T) {
+	unbalanced := map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, RegionCount: 100},
+		"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, RegionCount: 0},
+	}
+	balanced := map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, RegionCount: 100},
+		"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, RegionCount: 90},
+	}
+	warmingUp := func(since time.Time) []v1alpha1.TidbClusterCondition {
+		return []v1alpha1.TidbClusterCondition{{
+			Type:               v1alpha1.TidbClusterTiKVWarmedUp,
+			Status:             v1.ConditionFalse,
+			Reason:             utiltidbcluster.TiKVStoresWarmingUp,
+			LastTransitionTime: metav1.NewTime(since),
+		}}
+	}
+	tests := []struct {
+		name          string
+		percent       *int32
+		phase         v1alpha1.MemberPhase
+		stores        map[string]v1alpha1.TiKVStore
+		conditions    []v1alpha1.TidbClusterCondition
+		wantCondition bool
+		wantStatus    v1.ConditionStatus
+		wantReason    string
+	}{
+		{
+			name:          "percent is not set",
+			phase:         v1alpha1.ScalePhase,
+			stores:        unbalanced,
+			wantCondition: false,
+		},
+		{
+			name:          "tikv is being scaled",
+			percent:       pointer.Int32Ptr(50),
+			phase:         v1alpha1.ScalePhase,
+			stores:        balanced,
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiKVStoresWarmingUp,
+		},
+		{
+			name:          "stores are not balanced after scaling",
+			percent:       pointer.Int32Ptr(50),
+			phase:         v1alpha1.NormalPhase,
+			stores:        unbalanced,
+			conditions:    warmingUp(time.Now()),
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiKVStoresWarmingUp,
+		},
+		{
+			name:          "stores are balanced after scaling",
+			percent:       pointer.Int32Ptr(50),
+			phase:         v1alpha1.NormalPhase,
+			stores:        balanced,
+			conditions:    warmingUp(time.Now()),
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiKVStoresWarmedUp,
+		},
+		{
+			name:          "stores are not balanced in time",
+			percent:       pointer.Int32Ptr(50),
+			phase:         v1alpha1.NormalPhase,
+			stores:        unbalanced,
+			conditions:    warmingUp(time.Now().Add(-tikvWarmUpTimeout - time.Minute)),
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiKVWarmUpTimeout,
+		},
+		{
+			name:    "warmed up stores become unbalanced without scaling",
+			percent: pointer.Int32Ptr(50),
+			phase:   v1alpha1.NormalPhase,
+			stores:  unbalanced,
+			conditions: []v1alpha1.TidbClusterCondition{{
+				Type:   v1alpha1.TidbClusterTiKVWarmedUp,
+				Status: v1.ConditionTrue,
+				Reason: utiltidbcluster.TiKVStoresWarmedUp,
+			}},
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiKVStoresWarmedUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: &v1alpha1.TiKVSpec{
+						WarmUpRegionPercent: tt.percent,
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					TiKV: v1alpha1.TiKVStatus{
+						Phase:  tt.phase,
+						Stores: tt.stores,
+					},
+					Conditions: tt.conditions,
+				},
+			}
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterTiKVWarmedUp)
+			if !tt.wantCondition {
+				if cond != nil {
+					t.Errorf("unexpected condition: %v", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatalf("condition %s not found", v1alpha1.TidbClusterTiKVWarmedUp)
+			}
+			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReason, cond.Reason); diff != "" {
+				t.Errorf("unexpected reason (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_TiDBSQLReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		secret        *string
+		members       map[string]v1alpha1.TiDBMember
+		wantCondition bool
+		wantStatus    v1.ConditionStatus
+		wantReason    string
+		wantMessage   string
+	}{
+		{
+			name:   "sql health check is not enabled",
+			secret: nil,
+			members: map[string]v1alpha1.TiDBMember{
+				"test-tidb-0": {Name: "test-tidb-0", Health: true},
+			},
+			wantCondition: false,
+		},
+		{
+			name:   "all tidb instances can execute sql",
+			secret: pointer.StringPtr("sql-secret"),
+			members: map[string]v1alpha1.TiDBMember{
+				"test-tidb-0": {Name: "test-tidb-0", Health: true, SQLReady: true},
+				"test-tidb-1": {Name: "test-tidb-1", Health: true, SQLReady: true},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionTrue,
+			wantReason:    utiltidbcluster.TiDBSQLReady,
+			wantMessage:   "All TiDB instances can execute SQL",
+		},
+		{
+			name:   "some tidb instances can not execute sql",
+			secret: pointer.StringPtr("sql-secret"),
+			members: map[string]v1alpha1.TiDBMember{
+				"test-tidb-0": {Name: "test-tidb-0", Health: true, SQLReady: true},
+				"test-tidb-1": {Name: "test-tidb-1", Health: true, SQLReady: false},
+			},
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiDBSQLNotReady,
+			wantMessage:   "TiDB instance(s) of test-tidb-1 can not execute SQL",
+		},
+		{
+			name:          "no tidb instance",
+			secret:        pointer.StringPtr("sql-secret"),
+			wantCondition: true,
+			wantStatus:    v1.ConditionFalse,
+			wantReason:    utiltidbcluster.TiDBSQLNotReady,
+			wantMessage:   "No TiDB instance is available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					TiDB: &v1alpha1.TiDBSpec{
+						SQLHealthCheckSecret: tt.secret,
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					TiDB: v1alpha1.TiDBStatus{
+						Members: tt.members,
+					},
+				},
+			}
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterSQLReady)
+			if !tt.wantCondition {
+				if cond != nil {
+					t.Errorf("unexpected condition: %v", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatalf("condition %s not found", v1alpha1.TidbClusterSQLReady)
+			}
+			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReason, cond.Reason); diff != "" {
+				t.Errorf("unexpected reason (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMessage, cond.Message); diff != "" {
+				t.Errorf("unexpected message (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_ProgressingSuspendedDegraded(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      func(tc *v1alpha1.TidbCluster)
+		condType    v1alpha1.TidbClusterConditionType
+		wantStatus  v1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:        "all components are up to date",
+			update:      func(tc *v1alpha1.TidbCluster) {},
+			condType:    v1alpha1.TidbClusterProgressing,
+			wantStatus:  v1.ConditionFalse,
+			wantReason:  utiltidbcluster.ComponentsUpToDate,
+			wantMessage: "All components are up to date",
+		},
+		{
+			name: "components are being upgraded",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiDB.Phase = v1alpha1.ScalePhase
+			},
+			condType:    v1alpha1.TidbClusterProgressing,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.ComponentsUpgrading,
+			wantMessage: "PD,TiKV are being upgraded",
+		},
+		{
+			name: "components are being scaled",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Phase = v1alpha1.ScalePhase
+			},
+			condType:    v1alpha1.TidbClusterProgressing,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.ComponentsScaling,
+			wantMessage: "TiDB are being scaled",
+		},
+		{
+			name: "statefulset is not up to date",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.StatefulSet = &appsv1.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "2"}
+			},
+			condType:    v1alpha1.TidbClusterProgressing,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.StatfulSetNotUpToDate,
+			wantMessage: "Statefulset(s) are in progress",
+		},
+		{
+			name:        "not paused",
+			update:      func(tc *v1alpha1.TidbCluster) {},
+			condType:    v1alpha1.TidbClusterSuspended,
+			wantStatus:  v1.ConditionFalse,
+			wantReason:  utiltidbcluster.NotPaused,
+			wantMessage: "TiDB cluster is not paused",
+		},
+		{
+			name: "paused",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Paused = true
+			},
+			condType:    v1alpha1.TidbClusterSuspended,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.Paused,
+			wantMessage: "TiDB cluster is paused by spec.paused",
+		},
+		{
+			name: "components are suspended",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB = &v1alpha1.TiDBSpec{}
+				tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{Suspend: true}
+				tc.Spec.TiCDC = &v1alpha1.TiCDCSpec{Suspend: true}
+			},
+			condType:    v1alpha1.TidbClusterSuspended,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.ComponentsSuspended,
+			wantMessage: "tiflash,ticdc are suspended",
+		},
+		{
+			name:        "no failure",
+			update:      func(tc *v1alpha1.TidbCluster) {},
+			condType:    v1alpha1.TidbClusterDegraded,
+			wantStatus:  v1.ConditionFalse,
+			wantReason:  utiltidbcluster.NoFailure,
+			wantMessage: "No member has failed",
+		},
+		{
+			name: "failover in progress",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{"test-pd-0": {}}
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{"1": {}, "2": {}}
+			},
+			condType:    v1alpha1.TidbClusterDegraded,
+			wantStatus:  v1.ConditionTrue,
+			wantReason:  utiltidbcluster.FailoverInProgress,
+			wantMessage: "Failover is in progress for 1 PD,2 TiKV member(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &v1alpha1.TidbCluster{}
+			tc.Generation = 3
+			tt.update(tc)
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, tt.condType)
+			if cond == nil {
+				t.Fatalf("expected condition %s to be set", tt.condType)
+			}
+			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReason, cond.Reason); diff != "" {
+				t.Errorf("unexpected reason (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMessage, cond.Message); diff != "" {
+				t.Errorf("unexpected message (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(int64(3), cond.ObservedGeneration); diff != "" {
+				t.Errorf("unexpected observed generation (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_ObservedGeneration(t *testing.T) {
+	tc := &v1alpha1.TidbCluster{}
+	tc.Generation = 1
+	conditionUpdater := &tidbClusterConditionUpdater{}
+	conditionUpdater.Update(tc)
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterReady)
+	if cond.ObservedGeneration != 1 {
+		t.Errorf("expected observed generation 1, got %d", cond.ObservedGeneration)
+	}
+
+	// the condition is updated with the new generation even if status and reason are not changed
+	tc.Generation = 2
+	conditionUpdater.Update(tc)
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterReady)
+	if cond.ObservedGeneration != 2 {
+		t.Errorf("expected observed generation 2, got %d", cond.ObservedGeneration)
+	}
+}
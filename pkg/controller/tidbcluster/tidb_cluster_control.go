@@ -14,6 +14,7 @@
 package tidbcluster
 
 import (
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
@@ -21,8 +22,10 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
@@ -104,8 +107,11 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 	var errs []error
 	oldStatus := tc.Status.DeepCopy()
 
+	c.recordPausedEvent(tc)
 	if err := c.updateTidbCluster(tc); err != nil {
 		errs = append(errs, err)
+	} else {
+		setLastReconcile(tc, "", v1alpha1.ReconcileSucceeded, "", "")
 	}
 
 	if err := c.conditionUpdater.Update(tc); err != nil {
@@ -122,6 +128,19 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 	return errorutils.NewAggregate(errs)
 }
 
+// recordPausedEvent emits an event to explain why the components are not synced when the tidb
+// cluster is paused, it's only emitted once since the Suspended condition is set after the sync.
+func (c *defaultTidbClusterControl) recordPausedEvent(tc *v1alpha1.TidbCluster) {
+	if !tc.Spec.Paused {
+		return
+	}
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterSuspended)
+	if cond != nil && cond.Status == v1.ConditionTrue {
+		return
+	}
+	c.recorder.Event(tc, v1.EventTypeNormal, "Paused", "TidbCluster is paused by spec.paused, syncing the services and statefulsets of the components is skipped")
+}
+
 func (c *defaultTidbClusterControl) validate(tc *v1alpha1.TidbCluster) bool {
 	errs := v1alpha1validation.ValidateTidbCluster(tc)
 	if len(errs) > 0 {
@@ -141,14 +160,14 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	c.recordMetrics(tc)
 	// syncing all PVs managed by operator's reclaim policy to Retain
 	if err := c.reclaimPolicyManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "reclaim-policy", err)
 	}
 
 	// cleaning all orphan pods(pd, tikv or tiflash which don't have a related PVC) managed by operator
 	// this could be useful when failover run into an undesired situation as described in PD failover function
 	skipReasons, err := c.orphanPodsCleaner.Clean(tc)
 	if err != nil {
-		return err
+		return taskError(tc, "orphan-pods-cleaner", err)
 	}
 	if klog.V(10) {
 		for podName, reason := range skipReasons {
@@ -158,7 +177,7 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 
 	// reconcile TiDB discovery service
 	if err := c.discoveryManager.Reconcile(tc); err != nil {
-		return err
+		return taskError(tc, "discovery", err)
 	}
 
 	// works that should do to making the pd cluster current state match the desired state:
@@ -173,7 +192,7 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - scale out/in the pd cluster
 	//   - failover the pd cluster
 	if err := c.pdMemberManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "pd", err)
 	}
 
 	// works that should do to making the tikv cluster current state match the desired state:
@@ -186,12 +205,25 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - scale out/in the tikv cluster
 	//   - failover the tikv cluster
 	if err := c.tikvMemberManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "tikv", err)
+	}
+
+	// works that should do to making the tiflash cluster current state match the desired state:
+	//   - waiting for the pd cluster available(pd cluster is in quorum)
+	//   - create or update tiflash headless service
+	//   - create the tiflash statefulset
+	//   - sync tiflash cluster status from pd to TidbCluster object
+	//   - set scheduler labels to tiflash stores
+	//   - upgrade the tiflash cluster after the pd and tikv cluster are upgraded
+	//   - scale out/in the tiflash cluster
+	//   - failover the tiflash cluster
+	if err := c.tiflashMemberManager.Sync(tc); err != nil {
+		return taskError(tc, "tiflash", err)
 	}
 
 	// syncing the pump cluster
 	if err := c.pumpMemberManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "pump", err)
 	}
 
 	// works that should do to making the tidb cluster current state match the desired state:
@@ -199,31 +231,19 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - create or update tidb headless service
 	//   - create the tidb statefulset
 	//   - sync tidb cluster status from pd to TidbCluster object
-	//   - upgrade the tidb cluster
+	//   - upgrade the tidb cluster after the pd, tikv and tiflash cluster are upgraded
 	//   - scale out/in the tidb cluster
 	//   - failover the tidb cluster
 	if err := c.tidbMemberManager.Sync(tc); err != nil {
-		return err
-	}
-
-	// works that should do to making the tiflash cluster current state match the desired state:
-	//   - waiting for the tidb cluster available
-	//   - create or update tiflash headless service
-	//   - create the tiflash statefulset
-	//   - sync tiflash cluster status from pd to TidbCluster object
-	//   - set scheduler labels to tiflash stores
-	//   - upgrade the tiflash cluster
-	//   - scale out/in the tiflash cluster
-	//   - failover the tiflash cluster
-	if err := c.tiflashMemberManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "tidb", err)
 	}
 
 	//   - waiting for the pd cluster available(pd cluster is in quorum)
 	//   - create or update ticdc deployment
 	//   - sync ticdc cluster status from pd to TidbCluster object
+	//   - upgrade the ticdc cluster after all the other components are upgraded
 	if err := c.ticdcMemberManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "ticdc", err)
 	}
 
 	// syncing the labels from Pod to PVC and PV, these labels include:
@@ -231,13 +251,13 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - label.MemberIDLabelKey
 	//   - label.NamespaceLabelKey
 	if err := c.metaManager.Sync(tc); err != nil {
-		return err
+		return taskError(tc, "meta", err)
 	}
 
 	// cleaning the pod scheduling annotation for pd and tikv
 	pvcSkipReasons, err := c.pvcCleaner.Clean(tc)
 	if err != nil {
-		return err
+		return taskError(tc, "pvc-cleaner", err)
 	}
 	if klog.V(10) {
 		for pvcName, reason := range pvcSkipReasons {
@@ -247,12 +267,45 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 
 	// resize PVC if necessary
 	if err := c.pvcResizer.Resize(tc); err != nil {
-		return err
+		return taskError(tc, "pvc-resizer", err)
 	}
 
 	// syncing the some tidbcluster status attributes
 	// 	- sync tidbmonitor reference
-	return c.tidbClusterStatusManager.Sync(tc)
+	if err := c.tidbClusterStatusManager.Sync(tc); err != nil {
+		return taskError(tc, "status", err)
+	}
+	return nil
+}
+
+// taskError records the error returned by a task of the reconciliation in metrics
+// and in the status of the tidbcluster, requeue errors are not counted because they
+// are expected while waiting for a task to finish.
+func taskError(tc *v1alpha1.TidbCluster, task string, err error) error {
+	if requeueErr := perrors.Find(err, controller.IsRequeueError); requeueErr != nil {
+		setLastReconcile(tc, task, v1alpha1.ReconcileRequeued, requeueErr.(*controller.RequeueError).Reason(), err.Error())
+		return err
+	}
+	metrics.ReconcileTaskErrors.WithLabelValues(controllerName, task).Inc()
+	setLastReconcile(tc, task, v1alpha1.ReconcileFailed, "", err.Error())
+	return err
+}
+
+// setLastReconcile sets status.lastReconcile of the tidbcluster, the time is only
+// updated when the task, result, reason or message changes to avoid updating the
+// status on every reconciliation.
+func setLastReconcile(tc *v1alpha1.TidbCluster, task string, result v1alpha1.ReconcileResult, reason, message string) {
+	last := tc.Status.LastReconcile
+	if last != nil && last.Task == task && last.Result == result && last.Reason == reason && last.Message == message {
+		return
+	}
+	tc.Status.LastReconcile = &v1alpha1.ReconcileStatus{
+		Task:    task,
+		Result:  result,
+		Reason:  reason,
+		Message: message,
+		Time:    metav1.Now(),
+	}
 }
 
 func (c *defaultTidbClusterControl) recordMetrics(tc *v1alpha1.TidbCluster) {
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -294,6 +295,62 @@ func TestTidbClusterStatusEquality(t *testing.T) {
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
+func TestTaskErrorSetLastReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbClusterForTidbClusterControl()
+
+	err := taskError(tc, "tikv", controller.RequeueErrorf("tikv is upgrading"))
+	g.Expect(controller.IsRequeueError(err)).To(Equal(true))
+	g.Expect(tc.Status.LastReconcile).NotTo(BeNil())
+	g.Expect(tc.Status.LastReconcile.Task).To(Equal("tikv"))
+	g.Expect(tc.Status.LastReconcile.Result).To(Equal(v1alpha1.ReconcileRequeued))
+	g.Expect(tc.Status.LastReconcile.Message).To(Equal("tikv is upgrading"))
+
+	// the time is kept if nothing changes
+	lastTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	tc.Status.LastReconcile.Time = lastTime
+	taskError(tc, "tikv", controller.RequeueErrorf("tikv is upgrading"))
+	g.Expect(tc.Status.LastReconcile.Time).To(Equal(lastTime))
+
+	taskError(tc, "pd", fmt.Errorf("pd member manager sync error"))
+	g.Expect(tc.Status.LastReconcile.Task).To(Equal("pd"))
+	g.Expect(tc.Status.LastReconcile.Result).To(Equal(v1alpha1.ReconcileFailed))
+	g.Expect(tc.Status.LastReconcile.Time).NotTo(Equal(lastTime))
+
+	taskError(tc, "tikv", controller.RequeueAfterErrorf(10*time.Second, "WaitingForLeaderEviction", "tikv is evicting leader"))
+	g.Expect(tc.Status.LastReconcile.Task).To(Equal("tikv"))
+	g.Expect(tc.Status.LastReconcile.Result).To(Equal(v1alpha1.ReconcileRequeued))
+	g.Expect(tc.Status.LastReconcile.Reason).To(Equal("WaitingForLeaderEviction"))
+
+	setLastReconcile(tc, "", v1alpha1.ReconcileSucceeded, "", "")
+	g.Expect(tc.Status.LastReconcile.Task).To(BeEmpty())
+	g.Expect(tc.Status.LastReconcile.Reason).To(BeEmpty())
+	g.Expect(tc.Status.LastReconcile.Result).To(Equal(v1alpha1.ReconcileSucceeded))
+	g.Expect(tc.Status.LastReconcile.Message).To(BeEmpty())
+}
+
+func TestRecordPausedEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	c := &defaultTidbClusterControl{recorder: recorder}
+	tc := newTidbClusterForTidbClusterControl()
+
+	// not paused
+	c.recordPausedEvent(tc)
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// paused, the event is emitted
+	tc.Spec.Paused = true
+	c.recordPausedEvent(tc)
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("Paused"))
+
+	// the Suspended condition is set, the event is not emitted again
+	(&tidbClusterConditionUpdater{}).Update(tc)
+	c.recordPausedEvent(tc)
+	g.Expect(recorder.Events).To(BeEmpty())
+}
+
 func newFakeTidbClusterControl() (
 	ControlInterface,
 	*meta.FakeReclaimPolicyManager,
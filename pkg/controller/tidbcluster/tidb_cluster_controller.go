@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +33,9 @@ import (
 	"k8s.io/klog"
 )
 
+// controllerName is the name of the tidbcluster controller used in metrics
+const controllerName = "tidbcluster"
+
 // Controller controls tidbclusters.
 type Controller struct {
 	deps *controller.Dependencies
@@ -65,8 +69,8 @@ func NewController(deps *controller.Dependencies) *Controller {
 			deps.Recorder,
 		),
 		queue: workqueue.NewNamedRateLimitingQueue(
-			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
-			"tidbcluster",
+			controller.NewControllerRateLimiter(deps.CLIConfig.RateLimiterBaseDelay, deps.CLIConfig.RateLimiterMaxDelay, deps.CLIConfig.RateLimiterQPS, deps.CLIConfig.RateLimiterBurst),
+			controllerName,
 		),
 	}
 
@@ -119,16 +123,28 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
+	startTime := time.Now()
+	result := metrics.ReconcileSuccess
 	if err := c.sync(key.(string)); err != nil {
-		if perrors.Find(err, controller.IsRequeueError) != nil {
-			klog.Infof("TidbCluster: %v, still need sync: %v, requeuing", key.(string), err)
+		if requeueErr := perrors.Find(err, controller.IsRequeueError); requeueErr != nil {
+			result = metrics.ReconcileRequeue
+			if d := requeueErr.(*controller.RequeueError).Duration(); d > 0 {
+				klog.Infof("TidbCluster: %v, still need sync: %v, requeuing after %v", key.(string), err, d)
+				c.queue.Forget(key)
+				c.queue.AddAfter(key, d)
+			} else {
+				klog.Infof("TidbCluster: %v, still need sync: %v, requeuing", key.(string), err)
+				c.queue.AddRateLimited(key)
+			}
 		} else {
+			result = metrics.ReconcileError
 			utilruntime.HandleError(fmt.Errorf("TidbCluster: %v, sync failed %v, requeuing", key.(string), err))
+			c.queue.AddRateLimited(key)
 		}
-		c.queue.AddRateLimited(key)
 	} else {
 		c.queue.Forget(key)
 	}
+	metrics.ObserveReconcile(controllerName, result, time.Since(startTime).Seconds())
 	return true
 }
 
@@ -32,6 +32,9 @@ var (
 		AdvancedStatefulSet: false,
 		AutoScaling:         false,
 	}
+	// clusterScopedFeatures are the features which can be overridden by spec.featureGates of a cluster,
+	// the others affect the whole operator and can only be set by the flag.
+	clusterScopedFeatures = sets.NewString(StableScheduling)
 	// DefaultFeatureGate is a shared global FeatureGate.
 	DefaultFeatureGate FeatureGate = NewDefaultFeatureGate()
 )
@@ -134,3 +137,22 @@ func NewDefaultFeatureGate() FeatureGate {
 	f.SetFromMap(defaultFeatures)
 	return f
 }
+
+// ClusterScopedFeatures returns the features which can be overridden by spec.featureGates of a cluster.
+func ClusterScopedFeatures() []string {
+	return clusterScopedFeatures.List()
+}
+
+// IsClusterScoped returns true if the feature can be overridden by spec.featureGates of a cluster.
+func IsClusterScoped(key string) bool {
+	return clusterScopedFeatures.Has(key)
+}
+
+// EnabledInCluster returns true if the key is enabled for a cluster, the cluster feature gates
+// take priority over DefaultFeatureGate for the cluster scoped features.
+func EnabledInCluster(clusterGates map[string]bool, key string) bool {
+	if enabled, ok := clusterGates[key]; ok && IsClusterScoped(key) {
+		return enabled
+	}
+	return DefaultFeatureGate.Enabled(key)
+}
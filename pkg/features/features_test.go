@@ -13,7 +13,11 @@
 
 package features
 
-import "testing"
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
 
 func TestSet(t *testing.T) {
 	tests := []struct {
@@ -105,3 +109,50 @@ func TestEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestEnabledInCluster(t *testing.T) {
+	origGate, origScoped := DefaultFeatureGate, clusterScopedFeatures
+	defer func() {
+		DefaultFeatureGate, clusterScopedFeatures = origGate, origScoped
+	}()
+	DefaultFeatureGate = NewFeatureGate()
+	DefaultFeatureGate.SetFromMap(map[string]bool{"a": false, "b": true})
+	clusterScopedFeatures = sets.NewString("a")
+
+	tests := []struct {
+		name         string
+		clusterGates map[string]bool
+		wantEnabled  map[string]bool
+	}{
+		{
+			name:         "no cluster feature gates",
+			clusterGates: nil,
+			wantEnabled: map[string]bool{
+				"a": false,
+				"b": true,
+			},
+		},
+		{
+			name: "only cluster scoped features are overridden",
+			clusterGates: map[string]bool{
+				"a": true,
+				"b": false,
+			},
+			wantEnabled: map[string]bool{
+				"a": true,
+				"b": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, want := range tt.wantEnabled {
+				got := EnabledInCluster(tt.clusterGates, k)
+				if got != want {
+					t.Errorf("[feature: %s] want %v, got %v", k, want, got)
+				}
+			}
+		})
+	}
+}
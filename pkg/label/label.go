@@ -95,11 +95,33 @@ const (
 	AnnSysctlInit = "tidb.pingcap.com/sysctl-init"
 	// AnnEvictLeaderBeginTime is pod annotation key to indicate the begin time for evicting region leader
 	AnnEvictLeaderBeginTime = "tidb.pingcap.com/evictLeaderBeginTime"
+	// AnnTiKVEvictLeaderKey is tikv pod annotation key to evict the region leaders of the store
+	// during node maintenance, the leaders are allowed to come back once it is removed
+	AnnTiKVEvictLeaderKey = "tidb.pingcap.com/evict-leader"
+	// AnnTiCDCGracefulShutdownBeginTime is ticdc pod annotation key to indicate the begin time of
+	// draining the capture before the pod is restarted or removed
+	AnnTiCDCGracefulShutdownBeginTime = "tidb.pingcap.com/ticdc-graceful-shutdown-begin-time"
+	// AnnTiDBServerCertHash is tidb pod annotation key to record the hash of the TiDB server certificate,
+	// it triggers a rolling restart when the certificate is rotated
+	AnnTiDBServerCertHash = "tidb.pingcap.com/tidb-server-cert-hash"
+	// AnnPumpOfflineBeginTime is pump pod annotation key to indicate the begin time of
+	// making the pump node offline before the pod is removed
+	AnnPumpOfflineBeginTime = "tidb.pingcap.com/pump-offline-begin-time"
+	// AnnScaleInPriority is tikv pod annotation key to remove the pods with a higher priority
+	// first on scale-in, it takes effect when spec.tikv.scaleInPolicy is not HighestOrdinal
+	AnnScaleInPriority = "tidb.pingcap.com/scale-in-priority"
 	// AnnStsLastSyncTimestamp is sts annotation key to indicate the last timestamp the operator sync the sts
 	AnnStsLastSyncTimestamp = "tidb.pingcap.com/sync-timestamp"
+	// AnnAllowVersionDowngrade is tc annotation key to allow downgrading the version of PD and TiKV,
+	// which is rejected by the validation webhook by default
+	AnnAllowVersionDowngrade = "tidb.pingcap.com/allow-version-downgrade"
 
 	// AnnForceUpgradeVal is tc annotation value to indicate whether force upgrade should be done
 	AnnForceUpgradeVal = "true"
+	// AnnPauseUpgradeVal is tc annotation value to pause the rolling update of a component
+	AnnPauseUpgradeVal = "true"
+	// AnnAllowVersionDowngradeVal is tc annotation value to allow downgrading the version of PD and TiKV
+	AnnAllowVersionDowngradeVal = "true"
 	// AnnSysctlInitVal is pod annotation value to indicate whether configuring sysctls with init container
 	AnnSysctlInitVal = "true"
 
@@ -109,6 +131,20 @@ const (
 	AnnTiDBDeleteSlots = "tidb.tidb.pingcap.com/delete-slots"
 	// AnnTiKVDeleteSlots is annotation key of tikv delete slots.
 	AnnTiKVDeleteSlots = "tikv.tidb.pingcap.com/delete-slots"
+	// AnnPDPauseUpgrade is tc annotation key to pause the rolling update of pd, the statefulset
+	// is kept at the current partition while the value is "true"
+	AnnPDPauseUpgrade = "pd.tidb.pingcap.com/pause-upgrade"
+	// AnnTiKVPauseUpgrade is tc annotation key to pause the rolling update of tikv
+	AnnTiKVPauseUpgrade = "tikv.tidb.pingcap.com/pause-upgrade"
+	// AnnTiFlashPauseUpgrade is tc annotation key to pause the rolling update of tiflash
+	AnnTiFlashPauseUpgrade = "tiflash.tidb.pingcap.com/pause-upgrade"
+	// AnnTiDBPauseUpgrade is tc annotation key to pause the rolling update of tidb
+	AnnTiDBPauseUpgrade = "tidb.tidb.pingcap.com/pause-upgrade"
+	// AnnTiCDCPauseUpgrade is tc annotation key to pause the rolling update of ticdc
+	AnnTiCDCPauseUpgrade = "ticdc.tidb.pingcap.com/pause-upgrade"
+	// AnnTiKVCanaryPromote is tc annotation key to promote the canary rollout of tikv,
+	// the value is the update revision of the tikv statefulset to be promoted.
+	AnnTiKVCanaryPromote = "tikv.tidb.pingcap.com/canary-promote"
 	// AnnTiFlashDeleteSlots is annotation key of tiflash delete slots.
 	AnnTiFlashDeleteSlots = "tiflash.tidb.pingcap.com/delete-slots"
 	// AnnDMMasterDeleteSlots is annotation key of dm-master delete slots.
@@ -151,6 +187,8 @@ const (
 	BackupScheduleJobLabelVal string = "backup-schedule"
 	// InitJobLabelVal is TiDB initializer job label value
 	InitJobLabelVal string = "initializer"
+	// UpgradeHookJobLabelVal is upgrade hook job label value
+	UpgradeHookJobLabelVal string = "upgrade-hook"
 	// TiDBOperator is ManagedByLabelKey label value
 	TiDBOperator string = "tidb-operator"
 
@@ -294,6 +332,11 @@ func (l Label) RestoreJob() Label {
 	return l.Component(RestoreJobLabelVal)
 }
 
+// UpgradeHookJob assigns upgrade-hook to component key in label
+func (l Label) UpgradeHookJob() Label {
+	return l.Component(UpgradeHookJobLabelVal)
+}
+
 // Backup assigns specific value to backup key in label
 func (l Label) Backup(val string) Label {
 	l[BackupLabelKey] = val
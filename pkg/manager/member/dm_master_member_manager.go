@@ -609,12 +609,13 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			},
 			{
 				Name:          "client",
-				ContainerPort: int32(8261),
+				ContainerPort: v1alpha1.DefaultDMMasterPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(dc.Spec.Master.ResourceRequirements),
+		VolumeMounts:   volMounts,
+		Resources:      controller.ContainerResource(dc.Spec.Master.ResourceRequirements),
+		ReadinessProbe: buildTCPReadinessProbe(dc.Spec.Master.ReadinessProbe, v1alpha1.DefaultDMMasterPort),
 	}
 	env := []corev1.EnvVar{
 		{
@@ -644,8 +645,9 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	}
 
 	podSpec := baseMasterSpec.BuildPodSpec()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseMasterSpec.TopologySpreadConstraints(), masterLabel)
 	if baseMasterSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = baseMasterSpec.DnsPolicy()
 		env = append(env, corev1.EnvVar{
 			Name: "POD_NAME",
 			ValueFrom: &corev1.EnvVarSource{
@@ -658,6 +660,7 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	masterContainer.Env = util.AppendEnv(env, baseMasterSpec.Env())
 	podSpec.Volumes = append(vols, baseMasterSpec.AdditionalVolumes()...)
 	podSpec.Containers = append([]corev1.Container{masterContainer}, baseMasterSpec.AdditionalContainers()...)
+	podSpec.InitContainers = append(podSpec.InitContainers, baseMasterSpec.InitContainers()...)
 
 	masterSet := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -672,7 +675,7 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			Selector: masterLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      masterLabel.Labels(),
+					Labels:      CombineLabels(baseMasterSpec.Labels(), masterLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
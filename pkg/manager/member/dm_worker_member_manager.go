@@ -422,12 +422,13 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "client",
-				ContainerPort: int32(8262),
+				ContainerPort: v1alpha1.DefaultDMWorkerPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(dc.Spec.Worker.ResourceRequirements),
+		VolumeMounts:   volMounts,
+		Resources:      controller.ContainerResource(dc.Spec.Worker.ResourceRequirements),
+		ReadinessProbe: buildTCPReadinessProbe(dc.Spec.Worker.ReadinessProbe, v1alpha1.DefaultDMWorkerPort),
 	}
 	env := []corev1.EnvVar{
 		{
@@ -457,8 +458,9 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	}
 
 	podSpec := baseWorkerSpec.BuildPodSpec()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseWorkerSpec.TopologySpreadConstraints(), workerLabel)
 	if baseWorkerSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = baseWorkerSpec.DnsPolicy()
 		env = append(env, corev1.EnvVar{
 			Name: "POD_NAME",
 			ValueFrom: &corev1.EnvVarSource{
@@ -471,6 +473,7 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	workerContainer.Env = util.AppendEnv(env, baseWorkerSpec.Env())
 	podSpec.Volumes = append(vols, baseWorkerSpec.AdditionalVolumes()...)
 	podSpec.Containers = append([]corev1.Container{workerContainer}, baseWorkerSpec.AdditionalContainers()...)
+	podSpec.InitContainers = append(podSpec.InitContainers, baseWorkerSpec.InitContainers()...)
 
 	workerSet := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -485,7 +488,7 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 			Selector: workerLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      workerLabel.Labels(),
+					Labels:      CombineLabels(baseWorkerSpec.Labels(), workerLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
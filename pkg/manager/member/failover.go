@@ -22,6 +22,9 @@ const (
 	unHealthEventReason     = "Unhealthy"
 	unHealthEventMsgPattern = "%s pod[%s] is unhealthy, msg:%s"
 	FailedSetStoreLabels    = "FailedSetStoreLabels"
+	FailedSetStoreWeight    = "FailedSetStoreWeight"
+	FailedSetServerLabels   = "FailedSetServerLabels"
+	FailedReloadTLS         = "FailedReloadTLS"
 )
 
 // Failover implements the logic for pd/tikv/tidb's failover and recovery.
@@ -84,6 +84,12 @@ func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		return err
 	}
 
+	// Sync PD PodDisruptionBudget
+	pdSelector := label.New().Instance(tc.GetInstanceName()).PD()
+	if err := syncPodDisruptionBudget(m.deps, tc, controller.PDMemberName(tc.GetName()), pdSelector, pdMaxUnavailable(tc.Spec.PD.Replicas)); err != nil {
+		return err
+	}
+
 	// Sync PD StatefulSet
 	return m.syncPDStatefulSetForTidbCluster(tc)
 }
@@ -697,12 +703,13 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 			},
 			{
 				Name:          "client",
-				ContainerPort: int32(2379),
+				ContainerPort: v1alpha1.DefaultPDClientPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(tc.Spec.PD.ResourceRequirements),
+		VolumeMounts:   volMounts,
+		Resources:      controller.ContainerResource(tc.Spec.PD.ResourceRequirements),
+		ReadinessProbe: buildTCPReadinessProbe(tc.Spec.PD.ReadinessProbe, v1alpha1.DefaultPDClientPort),
 	}
 	env := []corev1.EnvVar{
 		{
@@ -732,8 +739,9 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 	}
 
 	podSpec := basePDSpec.BuildPodSpec()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(basePDSpec.TopologySpreadConstraints(), pdLabel)
 	if basePDSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = basePDSpec.DnsPolicy()
 		env = append(env, corev1.EnvVar{
 			Name: "POD_NAME",
 			ValueFrom: &corev1.EnvVarSource{
@@ -753,6 +761,11 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 	podSpec.SecurityContext = podSecurityContext
 	podSpec.InitContainers = append(initContainers, basePDSpec.InitContainers()...)
 
+	podSpec, err = applyPodSpecOverlay(podSpec, tc.Spec.PD.PodSpecOverlay)
+	if err != nil {
+		return nil, fmt.Errorf("apply pod spec overlay of statefulset %s/%s failed, err: %v", ns, setName, err)
+	}
+
 	updateStrategy := apps.StatefulSetUpdateStrategy{}
 	if basePDSpec.StatefulSetUpdateStrategy() == apps.OnDeleteStatefulSetStrategyType {
 		updateStrategy.Type = apps.OnDeleteStatefulSetStrategyType
@@ -776,7 +789,7 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 			Selector: pdLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      pdLabel.Labels(),
+					Labels:      CombineLabels(basePDSpec.Labels(), pdLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -38,6 +39,11 @@ func NewPDScaler(deps *controller.Dependencies) Scaler {
 }
 
 func (s *pdScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if tc, ok := meta.(*v1alpha1.TidbCluster); ok {
+		if err := s.applyTopologyEvenScaleIn(tc, oldSet, newSet); err != nil {
+			return err
+		}
+	}
 	scaling, _, _, _ := scaleOne(oldSet, newSet)
 	if scaling > 0 {
 		return s.ScaleOut(meta, oldSet, newSet)
@@ -138,14 +144,14 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 				targetOrdinal = minOrdinal
 			}
 			targetPdName := PdName(tcName, targetOrdinal, tc.Namespace, tc.Spec.ClusterDomain)
-			if _, exist := tc.Status.PD.Members[targetPdName]; exist {
-				err = pdClient.TransferPDLeader(targetPdName)
-			} else {
-				err = pdClient.TransferPDLeader(PdPodName(tcName, targetOrdinal))
+			if _, exist := tc.Status.PD.Members[targetPdName]; !exist {
+				targetPdName = PdPodName(tcName, targetOrdinal)
 			}
+			err = pdClient.TransferPDLeader(targetPdName)
 			if err != nil {
 				return err
 			}
+			s.deps.Recorder.Eventf(tc, v1.EventTypeNormal, "PDLeaderTransferred", "pd leader is transferred from %s to %s before scaling in", memberName, targetPdName)
 		} else {
 			for _, member := range tc.Status.PD.PeerMembers {
 				if member.Health && member.Name != memberName {
@@ -153,6 +159,7 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 					if err != nil {
 						return err
 					}
+					s.deps.Recorder.Eventf(tc, v1.EventTypeNormal, "PDLeaderTransferred", "pd leader is transferred from %s to peer member %s before scaling in", memberName, member.Name)
 					return controller.RequeueErrorf("tc[%s/%s]'s pd pod[%s/%s] is transferring pd leader,can't scale-in now", ns, tcName, ns, memberName)
 				}
 			}
@@ -165,6 +172,7 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 		return err
 	}
 	klog.Infof("pdScaler.ScaleIn: delete member %s successfully", memberName)
+	s.deps.Recorder.Eventf(tc, v1.EventTypeNormal, "PDMemberDeleted", "member %s is deleted from PD cluster for scaling in", memberName)
 
 	pod, err := s.deps.PodLister.Pods(ns).Get(pdPodName)
 	if err != nil {
@@ -186,6 +194,30 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 	return nil
 }
 
+// applyTopologyEvenScaleIn chooses the PD Pod to be removed on scale-in from the most populated
+// topology domain of the topology spread constraints of PD, so that no domain loses its last
+// PD member while another domain still has more than one.
+func (s *pdScaler) applyTopologyEvenScaleIn(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	constraints := tc.BasePDSpec().TopologySpreadConstraints()
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	ordinals, choose := scaleInCandidates(oldSet, newSet)
+	if !choose {
+		return nil
+	}
+	candidates, err := s.topologyEvenOrdinals(tc.GetNamespace(), constraints, ordinals, func(ordinal int32) string {
+		return PdPodName(tc.GetName(), ordinal)
+	})
+	if err != nil {
+		return err
+	}
+	ordinal := candidates[len(candidates)-1]
+	klog.Infof("pd of tc %s/%s chooses ordinal %d to be removed to keep the topology spread even", tc.Namespace, tc.Name, ordinal)
+	return setScaleInOrdinal(tc, label.AnnPDDeleteSlots, oldSet, newSet, ordinals, ordinal)
+}
+
 func (s *pdScaler) SyncAutoScalerAnn(meta metav1.Object, actual *apps.StatefulSet) error {
 	return nil
 }
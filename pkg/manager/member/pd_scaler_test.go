@@ -19,15 +19,18 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
 )
@@ -550,6 +553,83 @@ func TestPDScalerScaleInBlockByOtherComponents(t *testing.T) {
 	}
 }
 
+func TestPDScalerApplyTopologyEvenScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	features.DefaultFeatureGate.Set("AdvancedStatefulSet=true")
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.TopologySpreadConstraints = []v1alpha1.TopologySpreadConstraint{{TopologyKey: "zone"}}
+	scaler, _, _, podIndexer, _ := newFakePDScaler()
+	nodeIndexer := scaler.deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+	// z1: 0, 1, z2: 2, 3, z3: 4
+	zones := []string{"z1", "z1", "z2", "z2", "z3"}
+	for i, zone := range zones {
+		nodeName := fmt.Sprintf("node-%d", i)
+		nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName, Labels: map[string]string{"zone": zone}}})
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: PdPodName(tc.GetName(), int32(i)), Namespace: tc.GetNamespace()},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		})
+	}
+
+	oldSet := newStatefulSetForPDScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(3)
+	err := scaler.applyTopologyEvenScaleIn(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*newSet.Spec.Replicas).To(Equal(int32(4)))
+	g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(sets.NewInt32(3)))
+
+	g.Expect(tc.Annotations[label.AnnPDDeleteSlots]).To(Equal("[3]"))
+
+	// z1: 0, 1, z2: 2, z3: 4, the desired delete slots come from the annotation of the tc
+	helper.SetDeleteSlots(oldSet, sets.NewInt32(3))
+	oldSet.Spec.Replicas = pointer.Int32Ptr(4)
+	newSet = oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(3)
+	helper.SetDeleteSlots(newSet, sets.NewInt32(3))
+	err = scaler.applyTopologyEvenScaleIn(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*newSet.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(sets.NewInt32(1, 3)))
+	g.Expect(tc.Annotations[label.AnnPDDeleteSlots]).To(Equal("[1,3]"))
+
+	// the delete slot 1 is removed from the annotation of the tc, it's not kept
+	helper.SetDeleteSlots(oldSet, sets.NewInt32(1, 3))
+	oldSet.Spec.Replicas = pointer.Int32Ptr(3)
+	newSet = oldSet.DeepCopy()
+	helper.SetDeleteSlots(newSet, sets.NewInt32(3))
+	err = scaler.applyTopologyEvenScaleIn(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*newSet.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(sets.NewInt32(3)))
+
+	// pod 4 is missing, fall back to the highest ordinal
+	podIndexer.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: PdPodName(tc.GetName(), 4), Namespace: tc.GetNamespace()}})
+	tc.Annotations = nil
+	helper.SetDeleteSlots(oldSet, nil)
+	oldSet.Spec.Replicas = pointer.Int32Ptr(5)
+	newSet = oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(3)
+	err = scaler.applyTopologyEvenScaleIn(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*newSet.Spec.Replicas).To(Equal(int32(4)))
+	g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(sets.NewInt32()))
+	g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnPDDeleteSlots))
+
+	// no topology spread constraints
+	helper.SetDeleteSlots(oldSet, sets.NewInt32(3))
+	oldSet.Spec.Replicas = pointer.Int32Ptr(4)
+	tc.Spec.PD.TopologySpreadConstraints = nil
+	newSet = oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(3)
+	err = scaler.applyTopologyEvenScaleIn(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*newSet.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(sets.NewInt32(3)))
+}
+
 func newFakePDScaler() (*pdScaler, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, *controller.FakePVCControl) {
 	fakeDeps := controller.NewFakeDependencies()
 	pdScaler := &pdScaler{generalScaler: generalScaler{deps: fakeDeps}}
@@ -19,7 +19,9 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog"
 )
 
@@ -75,6 +77,10 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 	}
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	if upgradePaused(tc, label.AnnPDPauseUpgrade) {
+		klog.Infof("tidbcluster: [%s/%s]'s pd upgrade is paused by annotation %s", ns, tcName, label.AnnPDPauseUpgrade)
+		return nil
+	}
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
@@ -96,8 +102,13 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 			continue
 		}
 
+		if err := checkDisruptionAllowed(u.deps, tc, controller.PDMemberName(tcName)); err != nil {
+			return err
+		}
+
 		if u.deps.CLIConfig.PodWebhookEnabled {
 			setUpgradePartition(newSet, i)
+			u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDPodUpgrading", "pd pod %s/%s is recreated to update revision %s from %s", ns, podName, tc.Status.PD.StatefulSet.UpdateRevision, revision)
 			return nil
 		}
 
@@ -138,10 +149,12 @@ func (u *pdUpgrader) upgradePDPod(tc *v1alpha1.TidbCluster, ordinal int32, newSe
 				return err
 			}
 			klog.Infof("pd upgrader: transfer pd leader to: %s successfully", targetName)
+			u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDLeaderTransferred", "pd leader is transferred from %s to %s before upgrading pd pod %s/%s", upgradePdName, targetName, ns, upgradePodName)
 			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pd member: [%s] is transferring leader to pd member: [%s]", ns, tcName, upgradePdName, targetName)
 		}
 	}
 	setUpgradePartition(newSet, ordinal)
+	u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDPodUpgrading", "pd pod %s/%s is recreated to update revision %s", ns, upgradePodName, tc.Status.PD.StatefulSet.UpdateRevision)
 	return nil
 }
 
@@ -0,0 +1,106 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// pdMaxUnavailable returns the number of PD members which can be disrupted
+// without losing the quorum. A PD cluster with less than 3 members can not
+// tolerate any failure, so 1 is allowed to not block its rolling upgrade.
+func pdMaxUnavailable(replicas int32) int32 {
+	if replicas < 3 {
+		return 1
+	}
+	return replicas - (replicas/2 + 1)
+}
+
+// getNewPodDisruptionBudget returns the PodDisruptionBudget of a component which
+// allows at most maxUnavailable pods to be disrupted at a time.
+func getNewPodDisruptionBudget(tc *v1alpha1.TidbCluster, name string, selector label.Label, maxUnavailable int32) *policyv1beta1.PodDisruptionBudget {
+	maxUnavailableVal := intstr.FromInt(int(maxUnavailable))
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tc.GetNamespace(),
+			Labels:          selector.Copy().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailableVal,
+			Selector:       selector.LabelSelector(),
+		},
+	}
+}
+
+// syncPodDisruptionBudget creates or updates the PodDisruptionBudget of a component
+// if spec.enablePodDisruptionBudget is true, otherwise deletes the existing one.
+func syncPodDisruptionBudget(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, name string, selector label.Label, maxUnavailable int32) error {
+	if tc.Spec.Paused {
+		return nil
+	}
+
+	if tc.IsPodDisruptionBudgetEnabled() {
+		pdb := getNewPodDisruptionBudget(tc, name, selector, maxUnavailable)
+		_, err := deps.TypedControl.CreateOrUpdatePodDisruptionBudget(tc, pdb)
+		return err
+	}
+
+	ns := tc.GetNamespace()
+	pdb, err := deps.PDBLister.PodDisruptionBudgets(ns).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("syncPodDisruptionBudget: failed to get pdb %s/%s, error: %s", ns, name, err)
+	}
+	if !metav1.IsControlledBy(pdb, tc) {
+		return nil
+	}
+	return deps.TypedControl.Delete(tc, pdb.DeepCopy())
+}
+
+// checkDisruptionAllowed returns a requeue error if the PodDisruptionBudget of a component
+// does not allow any more disruption, e.g. a pod is being evicted by a node drain, so the
+// upgrader does not restart another pod at the same time.
+func checkDisruptionAllowed(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, name string) error {
+	if !tc.IsPodDisruptionBudgetEnabled() {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	pdb, err := deps.PDBLister.PodDisruptionBudgets(ns).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("checkDisruptionAllowed: failed to get pdb %s/%s, error: %s", ns, name, err)
+	}
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pdb %s is not observed yet", ns, tc.GetName(), name)
+	}
+	if pdb.Status.PodDisruptionsAllowed <= 0 {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pdb %s does not allow any more disruption, current healthy %d, desired healthy %d",
+			ns, tc.GetName(), name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPDMaxUnavailable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(pdMaxUnavailable(1)).To(Equal(int32(1)))
+	g.Expect(pdMaxUnavailable(2)).To(Equal(int32(1)))
+	g.Expect(pdMaxUnavailable(3)).To(Equal(int32(1)))
+	g.Expect(pdMaxUnavailable(4)).To(Equal(int32(1)))
+	g.Expect(pdMaxUnavailable(5)).To(Equal(int32(2)))
+}
+
+func TestSyncPodDisruptionBudget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	deps := controller.NewFakeDependencies()
+	name := controller.PDMemberName(tc.GetName())
+	selector := label.New().Instance(tc.GetInstanceName()).PD()
+	key := client.ObjectKey{Namespace: tc.GetNamespace(), Name: name}
+
+	// not enabled, nothing is created
+	err := syncPodDisruptionBudget(deps, tc, name, selector, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	exist, err := deps.TypedControl.Exist(key, &policyv1beta1.PodDisruptionBudget{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exist).To(BeFalse())
+
+	tc.Spec.EnablePodDisruptionBudget = pointer.BoolPtr(true)
+	err = syncPodDisruptionBudget(deps, tc, name, selector, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	exist, err = deps.TypedControl.Exist(key, pdb)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exist).To(BeTrue())
+	g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+	g.Expect(pdb.Spec.Selector).To(Equal(selector.LabelSelector()))
+	g.Expect(metav1.IsControlledBy(pdb, tc)).To(BeTrue())
+
+	// disabled again, the pdb is deleted
+	deps.KubeInformerFactory.Policy().V1beta1().PodDisruptionBudgets().Informer().GetIndexer().Add(pdb)
+	tc.Spec.EnablePodDisruptionBudget = pointer.BoolPtr(false)
+	err = syncPodDisruptionBudget(deps, tc, name, selector, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	exist, err = deps.TypedControl.Exist(key, &policyv1beta1.PodDisruptionBudget{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exist).To(BeFalse())
+}
+
+func TestCheckDisruptionAllowed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		enabled     bool
+		pdb         *policyv1beta1.PodDisruptionBudget
+		expectErrFn func(*GomegaWithT, error)
+	}
+
+	tests := []testcase{
+		{
+			name:    "not enabled",
+			enabled: false,
+			pdb: &policyv1beta1.PodDisruptionBudget{
+				Status: policyv1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: 0},
+			},
+			expectErrFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:    "pdb not found",
+			enabled: true,
+			expectErrFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:    "disruption allowed",
+			enabled: true,
+			pdb: &policyv1beta1.PodDisruptionBudget{
+				Status: policyv1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: 1},
+			},
+			expectErrFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:    "disruption not allowed",
+			enabled: true,
+			pdb: &policyv1beta1.PodDisruptionBudget{
+				Status: policyv1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: 0, CurrentHealthy: 2, DesiredHealthy: 2},
+			},
+			expectErrFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+		},
+		{
+			name:    "pdb not observed",
+			enabled: true,
+			pdb: &policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     policyv1beta1.PodDisruptionBudgetStatus{ObservedGeneration: 1, PodDisruptionsAllowed: 1},
+			},
+			expectErrFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.EnablePodDisruptionBudget = pointer.BoolPtr(test.enabled)
+		deps := controller.NewFakeDependencies()
+		name := controller.TiKVMemberName(tc.GetName())
+		if test.pdb != nil {
+			test.pdb.Name = name
+			test.pdb.Namespace = tc.GetNamespace()
+			deps.KubeInformerFactory.Policy().V1beta1().PodDisruptionBudgets().Informer().GetIndexer().Add(test.pdb)
+		}
+		test.expectErrFn(g, checkDisruptionAllowed(deps, tc, name))
+	}
+}
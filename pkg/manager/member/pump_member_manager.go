@@ -17,7 +17,9 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
@@ -101,9 +103,135 @@ func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return nil
 	}
 
+	if err := m.gracefulScaleIn(tc, oldPumpSet, newPumpSet); err != nil {
+		return err
+	}
+
 	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newPumpSet, oldPumpSet)
 }
 
+// gracefulScaleIn removes the pump Pods one by one, the pump node is made offline
+// before its Pod is removed, so that the binlogs on it are consumed by drainers.
+func (m *pumpMemberManager) gracefulScaleIn(tc *v1alpha1.TidbCluster, oldSet, newSet *apps.StatefulSet) error {
+	oldReplicas := *oldSet.Spec.Replicas
+	ordinals := helper.GetPodOrdinals(oldReplicas, oldSet).List()
+	if *newSet.Spec.Replicas >= oldReplicas {
+		return m.cleanOfflineBeginTime(tc, ordinals, -1)
+	}
+
+	ordinal := ordinals[len(ordinals)-1]
+	if err := m.cleanOfflineBeginTime(tc, ordinals, ordinal); err != nil {
+		return err
+	}
+	// an offline pump stops serving, ask another pump for the state of the node if any
+	queryOrdinal := ordinal
+	if len(ordinals) > 1 {
+		queryOrdinal = ordinals[0]
+	}
+	if err := m.offlinePump(tc, ordinal, queryOrdinal); err != nil {
+		// keep the replicas until the pump node is offline
+		*newSet.Spec.Replicas = oldReplicas
+		return err
+	}
+	klog.Infof("tidbcluster: [%s/%s] scale in pump pod %s", tc.GetNamespace(), tc.GetName(), pumpPodName(tc.GetName(), ordinal))
+	*newSet.Spec.Replicas = oldReplicas - 1
+	return nil
+}
+
+// cleanOfflineBeginTime removes the offline begin time annotation from the Pump Pods which are
+// no longer scale-in candidates, e.g. the scale-in is canceled, so that the timeout is not
+// inherited by the next scale-in.
+func (m *pumpMemberManager) cleanOfflineBeginTime(tc *v1alpha1.TidbCluster, ordinals []int32, candidate int32) error {
+	ns := tc.GetNamespace()
+	for _, ordinal := range ordinals {
+		if ordinal == candidate {
+			continue
+		}
+		podName := pumpPodName(tc.GetName(), ordinal)
+		pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cleanOfflineBeginTime: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+		}
+		if _, exist := pod.Annotations[label.AnnPumpOfflineBeginTime]; !exist {
+			continue
+		}
+		pod = pod.DeepCopy()
+		delete(pod.Annotations, label.AnnPumpOfflineBeginTime)
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+		klog.Infof("pump: remove pod %s/%s annotation %s successfully", ns, podName, label.AnnPumpOfflineBeginTime)
+	}
+	return nil
+}
+
+// offlinePump makes the pump node of the ordinal offline, it returns nil
+// once the node is offline, the state of the node is got from the pump of queryOrdinal.
+func (m *pumpMemberManager) offlinePump(tc *v1alpha1.TidbCluster, ordinal, queryOrdinal int32) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pumpPodName(tcName, ordinal)
+	nodeID := controller.PumpNodeID(tcName, ordinal)
+
+	pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("offlinePump: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+	beginTimeStr, offlineRequested := pod.Annotations[label.AnnPumpOfflineBeginTime]
+	if offlineRequested {
+		beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+		if err != nil {
+			klog.Errorf("pump: parse annotation %s of pod %s/%s failed, error: %v", label.AnnPumpOfflineBeginTime, ns, podName, err)
+			return nil
+		}
+		timeout := tc.PumpOfflineTimeout()
+		if time.Now().After(beginTime.Add(timeout)) {
+			klog.Infof("pump: taking pump node %s offline for pod %s/%s exceeds the timeout %v, continue to scale in", nodeID, ns, podName, timeout)
+			return nil
+		}
+	}
+
+	nodes, err := m.deps.PumpControl.GetNodesStatus(tc, queryOrdinal)
+	if err != nil {
+		if queryOrdinal == ordinal && offlineRequested {
+			// the pump stops serving after it is offline
+			klog.Infof("pump: pod %s/%s is not serving after going offline, error: %v", ns, podName, err)
+			return nil
+		}
+		return controller.RequeueErrorf("tidbcluster: [%s/%s] failed to get the state of pump node %s, error: %v", ns, tcName, nodeID, err)
+	}
+	node, exist := nodes[nodeID]
+	if !exist || node.State == controller.PumpNodeOffline {
+		return nil
+	}
+	if node.State == controller.PumpNodeClosing {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pump node %s is going offline", ns, tcName, nodeID)
+	}
+
+	if !offlineRequested {
+		pod = pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		now := time.Now().Format(time.RFC3339)
+		pod.Annotations[label.AnnPumpOfflineBeginTime] = now
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+		klog.Infof("pump: set pod %s/%s annotation %s to %s successfully", ns, podName, label.AnnPumpOfflineBeginTime, now)
+	}
+	if err := m.deps.PumpControl.OfflineNode(tc, ordinal); err != nil {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s] failed to offline pump node %s, error: %v", ns, tcName, nodeID, err)
+	}
+	return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pump node %s is going offline, state: %s", ns, tcName, nodeID, node.State)
+}
+
 func (m *pumpMemberManager) syncTiDBClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
 	if set == nil {
 		// skip if not created yet
@@ -314,6 +442,7 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			Name: pumpCertVolumeMount, ReadOnly: true, MountPath: pumpCertPath,
 		})
 	}
+	volumeMounts = append(volumeMounts, tc.Spec.Pump.AdditionalVolumeMounts...)
 	containers := []corev1.Container{
 		{
 			Name:            "pump",
@@ -326,13 +455,15 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			},
 			Ports: []corev1.ContainerPort{{
 				Name:          "pump",
-				ContainerPort: 8250,
+				ContainerPort: v1alpha1.DefaultPumpPort,
 			}},
-			Resources:    controller.ContainerResource(tc.Spec.Pump.ResourceRequirements),
-			Env:          util.AppendEnv(envs, spec.Env()),
-			VolumeMounts: volumeMounts,
+			Resources:      controller.ContainerResource(tc.Spec.Pump.ResourceRequirements),
+			Env:            util.AppendEnv(envs, spec.Env()),
+			VolumeMounts:   volumeMounts,
+			ReadinessProbe: buildTCPReadinessProbe(tc.Spec.Pump.ReadinessProbe, v1alpha1.DefaultPumpPort),
 		},
 	}
+	containers = append(containers, spec.AdditionalContainers()...)
 
 	// Keep backward compatibility for pump created by helm
 	volumes := []corev1.Volume{
@@ -363,6 +494,7 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			},
 		})
 	}
+	volumes = append(volumes, spec.AdditionalVolumes()...)
 
 	volumeClaims := []corev1.PersistentVolumeClaim{
 		{
@@ -387,24 +519,27 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 	podTemplate := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: podAnnos,
-			Labels:      pumpLabel,
+			Labels:      CombineLabels(spec.Labels(), pumpLabel),
 		},
 		Spec: corev1.PodSpec{
 			Containers:         containers,
 			ServiceAccountName: serviceAccountName,
 			Volumes:            volumes,
 
-			Affinity:         spec.Affinity(),
-			Tolerations:      spec.Tolerations(),
-			NodeSelector:     spec.NodeSelector(),
-			SchedulerName:    spec.SchedulerName(),
-			SecurityContext:  spec.PodSecurityContext(),
-			HostNetwork:      spec.HostNetwork(),
-			DNSPolicy:        spec.DnsPolicy(),
-			ImagePullSecrets: spec.ImagePullSecrets(),
-			InitContainers:   spec.InitContainers(),
+			Affinity:                      spec.Affinity(),
+			Tolerations:                   spec.Tolerations(),
+			NodeSelector:                  spec.NodeSelector(),
+			SchedulerName:                 spec.SchedulerName(),
+			SecurityContext:               spec.PodSecurityContext(),
+			HostNetwork:                   spec.HostNetwork(),
+			DNSPolicy:                     spec.DnsPolicy(),
+			DNSConfig:                     spec.DNSConfig(),
+			ImagePullSecrets:              spec.ImagePullSecrets(),
+			InitContainers:                spec.InitContainers(),
+			TerminationGracePeriodSeconds: spec.TerminationGracePeriodSeconds(),
 		},
 	}
+	podTemplate.Spec.TopologySpreadConstraints = getTopologySpreadConstraints(spec.TopologySpreadConstraints(), pumpLabel)
 
 	return &appsv1.StatefulSet{
 		ObjectMeta: objMeta,
@@ -18,11 +18,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/util/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -431,6 +433,173 @@ type pumpFakeControls struct {
 	generic *controller.FakeGenericControl
 }
 
+func TestPumpMemberManagerGracefulScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		oldReplicas    int32
+		newReplicas    int32
+		state          string
+		statusErr      bool
+		offlineBegun   bool
+		offlineExpired bool
+		annotated      []int32
+		errExpect      bool
+		expectReplicas int32
+		expectState    string
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		tc := newTidbClusterForPump()
+		pmm, _, _ := newFakePumpMemberManager()
+		pumpControl := pmm.deps.PumpControl.(*controller.FakePumpControl)
+		podIndexer := pmm.deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+
+		ordinal := test.oldReplicas - 1
+		for i := int32(0); i < test.oldReplicas; i++ {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pumpPodName(tc.GetName(), i),
+					Namespace: tc.GetNamespace(),
+				},
+			}
+			if i == ordinal && test.offlineBegun {
+				pod.Annotations = map[string]string{label.AnnPumpOfflineBeginTime: time.Now().Format(time.RFC3339)}
+			}
+			if i == ordinal && test.offlineExpired {
+				pod.Annotations = map[string]string{label.AnnPumpOfflineBeginTime: time.Now().Add(-time.Hour).Format(time.RFC3339)}
+			}
+			for _, annotated := range test.annotated {
+				if i == annotated {
+					pod.Annotations = map[string]string{label.AnnPumpOfflineBeginTime: time.Now().Format(time.RFC3339)}
+				}
+			}
+			podIndexer.Add(pod)
+		}
+		if test.state != "" {
+			pumpControl.SetNodeState(tc.GetName(), ordinal, test.state)
+		}
+		if test.statusErr {
+			pumpControl.SetGetNodesStatusError(fmt.Errorf("connection refused"))
+		}
+		oldSet := &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(test.oldReplicas),
+			},
+		}
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(test.newReplicas)
+
+		err := pmm.gracefulScaleIn(tc, oldSet, newSet)
+		if test.errExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(*newSet.Spec.Replicas).To(Equal(test.expectReplicas))
+		if test.expectState != "" {
+			pumpControl.SetGetNodesStatusError(nil)
+			nodes, err := pumpControl.GetNodesStatus(tc, 0)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(nodes[controller.PumpNodeID(tc.GetName(), ordinal)].State).To(Equal(test.expectState))
+			pod, err := pmm.deps.PodLister.Pods(tc.GetNamespace()).Get(pumpPodName(tc.GetName(), ordinal))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pod.Annotations).To(HaveKey(label.AnnPumpOfflineBeginTime))
+		}
+		for _, annotated := range test.annotated {
+			pod, err := pmm.deps.PodLister.Pods(tc.GetNamespace()).Get(pumpPodName(tc.GetName(), annotated))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pod.Annotations).NotTo(HaveKey(label.AnnPumpOfflineBeginTime))
+		}
+	}
+
+	tests := []*testcase{
+		{
+			name:           "scale out",
+			oldReplicas:    3,
+			newReplicas:    5,
+			expectReplicas: 5,
+		},
+		{
+			name:           "scale in is canceled",
+			oldReplicas:    3,
+			newReplicas:    3,
+			annotated:      []int32{2},
+			expectReplicas: 3,
+		},
+		{
+			name:           "pump node is online",
+			oldReplicas:    3,
+			newReplicas:    1,
+			state:          controller.PumpNodeOnline,
+			errExpect:      true,
+			expectReplicas: 3,
+			expectState:    controller.PumpNodeClosing,
+		},
+		{
+			name:           "pump node is going offline",
+			oldReplicas:    3,
+			newReplicas:    1,
+			state:          controller.PumpNodeClosing,
+			errExpect:      true,
+			expectReplicas: 3,
+		},
+		{
+			name:           "remove the annotation of the pump pod which is no longer the candidate",
+			oldReplicas:    3,
+			newReplicas:    1,
+			state:          controller.PumpNodeClosing,
+			annotated:      []int32{0},
+			errExpect:      true,
+			expectReplicas: 3,
+		},
+		{
+			name:           "pump node going offline exceeds the timeout",
+			oldReplicas:    3,
+			newReplicas:    1,
+			state:          controller.PumpNodeClosing,
+			offlineExpired: true,
+			expectReplicas: 2,
+		},
+		{
+			name:           "pump node is offline",
+			oldReplicas:    3,
+			newReplicas:    1,
+			state:          controller.PumpNodeOffline,
+			expectReplicas: 2,
+		},
+		{
+			name:           "pump node is not registered",
+			oldReplicas:    3,
+			newReplicas:    1,
+			expectReplicas: 2,
+		},
+		{
+			name:           "failed to get the state of pump node",
+			oldReplicas:    3,
+			newReplicas:    1,
+			statusErr:      true,
+			offlineBegun:   true,
+			errExpect:      true,
+			expectReplicas: 3,
+		},
+		{
+			name:           "last pump is not serving after going offline",
+			oldReplicas:    1,
+			newReplicas:    0,
+			statusErr:      true,
+			offlineBegun:   true,
+			expectReplicas: 0,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
+
 func newFakePumpMemberManager() (*pumpMemberManager, *pumpFakeControls, *pumpFakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
 	pmm := &pumpMemberManager{deps: fakeDeps}
@@ -502,6 +671,22 @@ func newTidbClusterForPump() *v1alpha1.TidbCluster {
 	}
 }
 
+func TestGetNewPumpStatefulSetTerminationGracePeriod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPump()
+	cm, err := getNewPumpConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	set, err := getNewPumpStatefulSet(tc, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeNil())
+
+	tc.Spec.Pump.TerminationGracePeriodSeconds = pointer.Int64Ptr(300)
+	set, err = getNewPumpStatefulSet(tc, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(pointer.Int64Ptr(300)))
+}
+
 func TestGetNewPumpHeadlessService(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -22,7 +22,9 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -161,6 +163,109 @@ func setReplicasAndDeleteSlots(newSet *apps.StatefulSet, replicas int32, deleteS
 		newSet.GetNamespace(), newSet.GetName(), oldReplicas, replicas)
 }
 
+// scaleInCandidates returns the ordinals of the Pods from which the one to be removed can be
+// chosen, it's false if the statefulset is not scaling in or the Pods to be removed are specified
+// by the delete slots explicitly. Choosing requires the AdvancedStatefulSet feature. The delete
+// slots of the desired statefulset come from the annotation of the TidbCluster only, so removing
+// an ordinal from the annotation brings the Pod back.
+func scaleInCandidates(oldSet *apps.StatefulSet, newSet *apps.StatefulSet) ([]int32, bool) {
+	scalingIn := *newSet.Spec.Replicas < *oldSet.Spec.Replicas
+	if !features.DefaultFeatureGate.Enabled(features.AdvancedStatefulSet) {
+		if scalingIn {
+			klog.Warningf("choosing the pod to be removed from statefulset %s/%s requires the AdvancedStatefulSet feature, remove the pod with the highest ordinal",
+				oldSet.Namespace, oldSet.Name)
+		}
+		return nil, false
+	}
+
+	desiredDeleteSlots := helper.GetDeleteSlots(newSet)
+	actualPodOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet)
+	if !scalingIn || actualPodOrdinals.HasAny(desiredDeleteSlots.List()...) {
+		return nil, false
+	}
+	return actualPodOrdinals.List(), true
+}
+
+// setScaleInOrdinal scales in the desired statefulset by one Pod. Unless it's the highest one in
+// ordinals, the ordinal is added to the delete slots of the desired statefulset and recorded in
+// the delete slots annotation of the TidbCluster, so that the Pod is not scaled out again in the
+// next round.
+func setScaleInOrdinal(tc *v1alpha1.TidbCluster, annKey string, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, ordinals []int32, ordinal int32) error {
+	*newSet.Spec.Replicas = *oldSet.Spec.Replicas - 1
+	if ordinal == ordinals[len(ordinals)-1] {
+		return nil
+	}
+	deleteSlots := helper.GetDeleteSlots(newSet)
+	deleteSlots.Insert(ordinal)
+	helper.SetDeleteSlots(newSet, deleteSlots)
+
+	v, err := util.Encode(deleteSlots.List())
+	if err != nil {
+		return err
+	}
+	if tc.Annotations == nil {
+		tc.Annotations = map[string]string{}
+	}
+	tc.Annotations[annKey] = v
+	return nil
+}
+
+// topologyEvenOrdinals narrows down ordinals to the Pods in the most populated topology domain,
+// for each topology spread constraint in turn, so that removing any of them keeps the Pods
+// spread evenly. Pods which are not scheduled yet are in the empty domain. If any Pod is not
+// found, it falls back to the highest ordinal.
+func (s *generalScaler) topologyEvenOrdinals(ns string, constraints []v1alpha1.TopologySpreadConstraint, ordinals []int32, podName func(int32) string) ([]int32, error) {
+	if len(constraints) == 0 {
+		return ordinals, nil
+	}
+
+	nodeLabels := map[int32]map[string]string{}
+	for _, ordinal := range ordinals {
+		name := podName(ordinal)
+		pod, err := s.deps.PodLister.Pods(ns).Get(name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				klog.Warningf("topologyEvenOrdinals: pod %s/%s is not found, fall back to the highest ordinal", ns, name)
+				return ordinals[len(ordinals)-1:], nil
+			}
+			return nil, fmt.Errorf("topologyEvenOrdinals: failed to get pod %s/%s, error: %s", ns, name, err)
+		}
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		node, err := s.deps.NodeLister.Get(pod.Spec.NodeName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("topologyEvenOrdinals: failed to get node %s of pod %s/%s, error: %s", pod.Spec.NodeName, ns, name, err)
+		}
+		nodeLabels[ordinal] = node.Labels
+	}
+
+	candidates := ordinals
+	for _, constraint := range constraints {
+		count := map[string]int{}
+		for _, ordinal := range ordinals {
+			count[nodeLabels[ordinal][constraint.TopologyKey]]++
+		}
+		max := 0
+		for _, ordinal := range candidates {
+			if c := count[nodeLabels[ordinal][constraint.TopologyKey]]; c > max {
+				max = c
+			}
+		}
+		var next []int32
+		for _, ordinal := range candidates {
+			if count[nodeLabels[ordinal][constraint.TopologyKey]] == max {
+				next = append(next, ordinal)
+			}
+		}
+		candidates = next
+	}
+	return candidates, nil
+}
+
 func ordinalPVCName(memberType v1alpha1.MemberType, setName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%s-%d", memberType, setName, ordinal)
 }
@@ -865,3 +865,50 @@ func newPVC(tc *v1alpha1.TidbCluster, index string, anno string) *corev1.Persist
 		},
 	}
 }
+
+func TestGeneralScalerTopologyEvenOrdinals(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	podIndexer := deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	nodeIndexer := deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+	scaler := &generalScaler{deps: deps}
+
+	nodes := map[string]map[string]string{
+		"node-1": {"zone": "z1", "host": "h1"},
+		"node-2": {"zone": "z1", "host": "h2"},
+		"node-3": {"zone": "z2", "host": "h3"},
+	}
+	for name, labels := range nodes {
+		nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+	}
+	// z1: 0, 1, 4 (h1: 0, 1, h2: 4), z2: 2, 3, ordinal 5 is not scheduled
+	podNodes := []string{"node-1", "node-1", "node-3", "node-3", "node-2", ""}
+	podName := func(ordinal int32) string {
+		return fmt.Sprintf("test-pd-%d", ordinal)
+	}
+	for i, nodeName := range podNodes {
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName(int32(i)), Namespace: corev1.NamespaceDefault},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		})
+	}
+
+	ordinals := []int32{0, 1, 2, 3, 4, 5}
+	got, err := scaler.topologyEvenOrdinals(corev1.NamespaceDefault, nil, ordinals, podName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(ordinals))
+
+	got, err = scaler.topologyEvenOrdinals(corev1.NamespaceDefault, []v1alpha1.TopologySpreadConstraint{{TopologyKey: "zone"}}, ordinals, podName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]int32{0, 1, 4}))
+
+	got, err = scaler.topologyEvenOrdinals(corev1.NamespaceDefault, []v1alpha1.TopologySpreadConstraint{{TopologyKey: "zone"}, {TopologyKey: "host"}}, ordinals, podName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]int32{0, 1}))
+
+	// pod 6 is not found
+	got, err = scaler.topologyEvenOrdinals(corev1.NamespaceDefault, []v1alpha1.TopologySpreadConstraint{{TopologyKey: "zone"}}, []int32{0, 1, 6}, podName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]int32{6}))
+}
@@ -86,8 +86,8 @@ ARGS="${ARGS}  --plugin-dir  {{ .PluginDirectory  }} --plugin-load {{ .PluginLis
 {{- end }}
 
 echo "start tidb-server ..."
-echo "/tidb-server ${ARGS}"
-exec /tidb-server ${ARGS}
+echo "{{ if .NumactlArgs }}numactl {{ .NumactlArgs }} {{ end }}/tidb-server ${ARGS}"
+exec {{ if .NumactlArgs }}numactl {{ .NumactlArgs }} {{ end }}/tidb-server ${ARGS}
 `))
 
 type TidbStartScriptModel struct {
@@ -96,6 +96,8 @@ type TidbStartScriptModel struct {
 	PluginList      string
 	ClusterDomain   string
 	Path            string
+	// NumactlArgs are the arguments of numactl to bind tidb-server to a NUMA node
+	NumactlArgs string
 }
 
 func (t *TidbStartScriptModel) FormatClusterDomain() string {
@@ -277,8 +279,8 @@ if [ ! -z "${STORE_LABELS:-}" ]; then
 fi
 
 echo "starting tikv-server ..."
-echo "/tikv-server ${ARGS}"
-exec /tikv-server ${ARGS}
+echo "{{ if .NumactlArgs }}numactl {{ .NumactlArgs }} {{ end }}/tikv-server ${ARGS}"
+exec {{ if .NumactlArgs }}numactl {{ .NumactlArgs }} {{ end }}/tikv-server ${ARGS}
 `))
 
 type TiKVStartScriptModel struct {
@@ -287,6 +289,8 @@ type TiKVStartScriptModel struct {
 	DataDir                   string
 	ClusterDomain             string
 	PDAddress                 string
+	// NumactlArgs are the arguments of numactl to bind tikv-server to a NUMA node
+	NumactlArgs string
 }
 
 func (t *TiKVStartScriptModel) FormatClusterDomain() string {
@@ -43,13 +43,15 @@ const (
 // ticdcMemberManager implements manager.Manager.
 type ticdcMemberManager struct {
 	deps                     *controller.Dependencies
+	ticdcUpgrader            Upgrader
 	statefulSetIsUpgradingFn func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
 }
 
 // NewTiCDCMemberManager returns a *ticdcMemberManager
 func NewTiCDCMemberManager(deps *controller.Dependencies) manager.Manager {
 	m := &ticdcMemberManager{
-		deps: deps,
+		deps:          deps,
+		ticdcUpgrader: NewTiCDCUpgrader(deps),
 	}
 	m.statefulSetIsUpgradingFn = ticdcStatefulSetIsUpgrading
 	return m
@@ -97,6 +99,11 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 			ns, tcName, err)
 	}
 
+	if tc.TiCDCSuspended() {
+		klog.V(4).Infof("ticdc of tidb cluster %s/%s is suspended, skip syncing for ticdc statefulset", ns, tcName)
+		return suspendStatefulSet(m.deps.StatefulSetControl, tc, oldSts)
+	}
+
 	newSts, err := getNewTiCDCStatefulSet(tc)
 	if err != nil {
 		return err
@@ -114,14 +121,85 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
-	if tc.PDUpgrading() || tc.TiKVUpgrading() {
-		klog.Warningf("pd or tikv is upgrading, skipping upgrade ticdc")
+	// TiCDC is upgraded after all the other components
+	if tc.PDUpgrading() || tc.TiKVUpgrading() || tc.TiFlashUpgrading() || tc.TiDBUpgrading() {
+		klog.Warningf("pd, tikv, tiflash or tidb is upgrading, skipping upgrade ticdc")
 		return nil
 	}
 
+	if err := m.gracefulScaleIn(tc, oldSts, newSts); err != nil {
+		return err
+	}
+
+	if !templateEqual(newSts, oldSts) || tc.Status.TiCDC.Phase == v1alpha1.UpgradePhase {
+		if err := m.ticdcUpgrader.Upgrade(tc, oldSts, newSts); err != nil {
+			return err
+		}
+	}
+
 	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newSts, oldSts)
 }
 
+// gracefulScaleIn removes the TiCDC Pods one by one, the capture of the Pod is
+// drained before it is removed.
+func (m *ticdcMemberManager) gracefulScaleIn(tc *v1alpha1.TidbCluster, oldSts, newSts *apps.StatefulSet) error {
+	oldReplicas := *oldSts.Spec.Replicas
+	ordinals := helper.GetPodOrdinals(oldReplicas, oldSts).List()
+	if *newSts.Spec.Replicas >= oldReplicas {
+		return m.cleanGracefulShutdownBeginTime(tc, ordinals, -1)
+	}
+
+	ordinal := ordinals[len(ordinals)-1]
+	if err := m.cleanGracefulShutdownBeginTime(tc, ordinals, ordinal); err != nil {
+		return err
+	}
+	if err := gracefulShutdownTiCDC(m.deps, tc, ordinal); err != nil {
+		// keep the replicas until the capture is drained
+		*newSts.Spec.Replicas = oldReplicas
+		return err
+	}
+	klog.Infof("tidbcluster: [%s/%s] scale in ticdc pod %s", tc.GetNamespace(), tc.GetName(), ticdcPodName(tc.GetName(), ordinal))
+	*newSts.Spec.Replicas = oldReplicas - 1
+	return nil
+}
+
+// cleanGracefulShutdownBeginTime removes the graceful shutdown begin time annotation from the
+// TiCDC Pods which are no longer scale-in candidates, e.g. the scale-in is canceled, so that the
+// timeout is not inherited by the next scale-in. The Pods which are not upgraded yet are skipped,
+// the annotation of them may be set by the upgrader.
+func (m *ticdcMemberManager) cleanGracefulShutdownBeginTime(tc *v1alpha1.TidbCluster, ordinals []int32, candidate int32) error {
+	if tc.Status.TiCDC.StatefulSet == nil {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	for _, ordinal := range ordinals {
+		if ordinal == candidate {
+			continue
+		}
+		podName := ticdcPodName(tc.GetName(), ordinal)
+		pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cleanGracefulShutdownBeginTime: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+		}
+		if _, exist := pod.Annotations[label.AnnTiCDCGracefulShutdownBeginTime]; !exist {
+			continue
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != tc.Status.TiCDC.StatefulSet.UpdateRevision {
+			continue
+		}
+		pod = pod.DeepCopy()
+		delete(pod.Annotations, label.AnnTiCDCGracefulShutdownBeginTime)
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+		klog.Infof("ticdc: remove pod %s/%s annotation %s successfully", ns, podName, label.AnnTiCDCGracefulShutdownBeginTime)
+	}
+	return nil
+}
+
 func (m *ticdcMemberManager) syncTiCDCStatus(tc *v1alpha1.TidbCluster, sts *apps.StatefulSet) error {
 	if sts == nil {
 		// skip if not created yet
@@ -287,12 +365,13 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "ticdc",
-				ContainerPort: int32(8301),
+				ContainerPort: v1alpha1.DefaultTiCDCPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Resources: controller.ContainerResource(tc.Spec.TiCDC.ResourceRequirements),
-		Env:       util.AppendEnv(envs, baseTiCDCSpec.Env()),
+		Resources:      controller.ContainerResource(tc.Spec.TiCDC.ResourceRequirements),
+		Env:            util.AppendEnv(envs, baseTiCDCSpec.Env()),
+		ReadinessProbe: buildTCPReadinessProbe(tc.Spec.TiCDC.ReadinessProbe, v1alpha1.DefaultTiCDCPort),
 	}
 
 	if tc.IsTLSClusterEnabled() {
@@ -310,8 +389,11 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 		}
 	}
 
+	ticdcContainer.VolumeMounts = append(ticdcContainer.VolumeMounts, tc.Spec.TiCDC.AdditionalVolumeMounts...)
+
 	podSpec := baseTiCDCSpec.BuildPodSpec()
-	podSpec.Containers = []corev1.Container{ticdcContainer}
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseTiCDCSpec.TopologySpreadConstraints(), ticdcLabel)
+	podSpec.Containers = append([]corev1.Container{ticdcContainer}, baseTiCDCSpec.AdditionalContainers()...)
 	podSpec.ServiceAccountName = tc.Spec.TiCDC.ServiceAccount
 	podSpec.InitContainers = append(podSpec.InitContainers, baseTiCDCSpec.InitContainers()...)
 	if podSpec.ServiceAccountName == "" {
@@ -336,6 +418,17 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 			},
 		}
 	}
+	podSpec.Volumes = append(podSpec.Volumes, baseTiCDCSpec.AdditionalVolumes()...)
+
+	updateStrategy := apps.StatefulSetUpdateStrategy{}
+	if baseTiCDCSpec.StatefulSetUpdateStrategy() == apps.OnDeleteStatefulSetStrategyType {
+		updateStrategy.Type = apps.OnDeleteStatefulSetStrategyType
+	} else {
+		updateStrategy.Type = apps.RollingUpdateStatefulSetStrategyType
+		updateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{
+			Partition: pointer.Int32Ptr(tc.TiCDCDeployDesiredReplicas()),
+		}
+	}
 
 	ticdcSts := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -350,16 +443,14 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 			Selector: ticdcLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      ticdcLabel.Labels(),
+					Labels:      CombineLabels(baseTiCDCSpec.Labels(), ticdcLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
 			},
 			ServiceName:         headlessSvcName,
 			PodManagementPolicy: apps.ParallelPodManagement,
-			UpdateStrategy: apps.StatefulSetUpdateStrategy{
-				Type: baseTiCDCSpec.StatefulSetUpdateStrategy(),
-			},
+			UpdateStrategy:      updateStrategy,
 		},
 	}
 	return ticdcSts, nil
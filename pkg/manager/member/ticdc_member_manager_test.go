@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -406,10 +407,140 @@ func TestTiCDCMemberManagerSyncTidbClusterStatus(t *testing.T) {
 	}
 }
 
+func TestTiCDCMemberManagerGracefulScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name            string
+		newReplicas     int32
+		tableCount      map[string]int
+		annotated       []int32
+		outdated        []int32
+		errExpect       bool
+		expectReplicas  int32
+		expectAnnotated []int32
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		tc := newTidbClusterForCDC()
+		tmm, _, _, indexers := newFakeTiCDCMemberManager()
+		cdcControl := tmm.deps.CDCControl.(*controller.FakeTiCDCControl)
+		cdcControl.SetTableCount(test.tableCount)
+
+		tc.Status.TiCDC.Captures = map[string]v1alpha1.TiCDCCapture{}
+		tc.Status.TiCDC.StatefulSet = &apps.StatefulSetStatus{UpdateRevision: "new"}
+		for i := int32(0); i < 3; i++ {
+			podName := ticdcPodName(tc.GetName(), i)
+			tc.Status.TiCDC.Captures[podName] = v1alpha1.TiCDCCapture{PodName: podName}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        podName,
+					Namespace:   tc.GetNamespace(),
+					Labels:      map[string]string{apps.ControllerRevisionHashLabelKey: "new"},
+					Annotations: map[string]string{},
+				},
+			}
+			for _, ordinal := range test.annotated {
+				if ordinal == i {
+					pod.Annotations[label.AnnTiCDCGracefulShutdownBeginTime] = time.Now().Format(time.RFC3339)
+				}
+			}
+			for _, ordinal := range test.outdated {
+				if ordinal == i {
+					pod.Labels[apps.ControllerRevisionHashLabelKey] = "old"
+				}
+			}
+			indexers.pod.Add(pod)
+		}
+		oldSts := &apps.StatefulSet{
+			Spec: apps.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(3),
+			},
+		}
+		newSts := oldSts.DeepCopy()
+		newSts.Spec.Replicas = pointer.Int32Ptr(test.newReplicas)
+
+		err := tmm.gracefulScaleIn(tc, oldSts, newSts)
+		if test.errExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(*newSts.Spec.Replicas).To(Equal(test.expectReplicas))
+		annotated := []int32{}
+		for i := int32(0); i < 3; i++ {
+			pod, err := tmm.deps.PodLister.Pods(tc.GetNamespace()).Get(ticdcPodName(tc.GetName(), i))
+			g.Expect(err).NotTo(HaveOccurred())
+			if _, exist := pod.Annotations[label.AnnTiCDCGracefulShutdownBeginTime]; exist {
+				annotated = append(annotated, i)
+			}
+		}
+		if test.expectAnnotated == nil {
+			test.expectAnnotated = []int32{}
+		}
+		g.Expect(annotated).To(Equal(test.expectAnnotated))
+	}
+
+	tests := []*testcase{
+		{
+			name:           "scale out",
+			newReplicas:    5,
+			expectReplicas: 5,
+		},
+		{
+			name:           "scale in is canceled",
+			newReplicas:    3,
+			annotated:      []int32{2},
+			expectReplicas: 3,
+		},
+		{
+			name:            "scale in is canceled but the pod is not upgraded",
+			newReplicas:     3,
+			annotated:       []int32{2},
+			outdated:        []int32{2},
+			expectReplicas:  3,
+			expectAnnotated: []int32{2},
+		},
+		{
+			name:            "scale in one by one",
+			newReplicas:     1,
+			expectReplicas:  2,
+			expectAnnotated: []int32{2},
+		},
+		{
+			name:        "capture is draining",
+			newReplicas: 1,
+			tableCount: map[string]int{
+				"test-ticdc-2": 1,
+			},
+			errExpect:       true,
+			expectReplicas:  3,
+			expectAnnotated: []int32{2},
+		},
+		{
+			name:        "pod is no longer the scale-in candidate",
+			newReplicas: 1,
+			annotated:   []int32{1, 2},
+			tableCount: map[string]int{
+				"test-ticdc-2": 1,
+			},
+			errExpect:       true,
+			expectReplicas:  3,
+			expectAnnotated: []int32{2},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
+
 func newFakeTiCDCMemberManager() (*ticdcMemberManager, *controller.FakeStatefulSetControl, *controller.FakeTiDBControl, *fakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
 	tmm := &ticdcMemberManager{
-		deps: fakeDeps,
+		deps:          fakeDeps,
+		ticdcUpgrader: NewFakeTiCDCUpgrader(),
 	}
 	tmm.statefulSetIsUpgradingFn = ticdcStatefulSetIsUpgrading
 	indexers := &fakeIndexers{
@@ -425,6 +556,35 @@ func newFakeTiCDCMemberManager() (*ticdcMemberManager, *controller.FakeStatefulS
 	return tmm, setControl, tidbControl, indexers
 }
 
+func TestGetNewTiCDCStatefulSetAdditionalContainers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForCDC()
+	set, err := getNewTiCDCStatefulSet(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Spec.Containers).To(HaveLen(1))
+	g.Expect(set.Spec.Template.Spec.Volumes).To(BeNil())
+
+	sidecar := corev1.Container{
+		Name:         "log-shipper",
+		Image:        "fluent-bit",
+		VolumeMounts: []corev1.VolumeMount{{Name: "sort-dir", MountPath: "/var/lib/sort-dir"}},
+	}
+	tc.Spec.TiCDC.AdditionalContainers = []corev1.Container{sidecar}
+	tc.Spec.TiCDC.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+	tc.Spec.TiCDC.AdditionalVolumes = []corev1.Volume{{Name: "sort-dir"}}
+	tc.Spec.TiCDC.AdditionalVolumeMounts = []corev1.VolumeMount{{Name: "sort-dir", MountPath: "/var/lib/sort-dir"}}
+	set, err = getNewTiCDCStatefulSet(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	podSpec := set.Spec.Template.Spec
+	g.Expect(podSpec.Containers).To(HaveLen(2))
+	g.Expect(podSpec.Containers[0].Name).To(Equal(v1alpha1.TiCDCMemberType.String()))
+	g.Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "sort-dir", MountPath: "/var/lib/sort-dir"}))
+	g.Expect(podSpec.Containers[1]).To(Equal(sidecar))
+	g.Expect(podSpec.InitContainers).To(HaveLen(1))
+	g.Expect(podSpec.Volumes).To(Equal([]corev1.Volume{{Name: "sort-dir"}}))
+}
+
 func newTidbClusterForCDC() *v1alpha1.TidbCluster {
 	return &v1alpha1.TidbCluster{
 		TypeMeta: metav1.TypeMeta{
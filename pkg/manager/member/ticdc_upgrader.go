@@ -0,0 +1,178 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/klog"
+)
+
+type ticdcUpgrader struct {
+	deps *controller.Dependencies
+}
+
+// NewTiCDCUpgrader returns a ticdc Upgrader
+func NewTiCDCUpgrader(deps *controller.Dependencies) Upgrader {
+	return &ticdcUpgrader{
+		deps: deps,
+	}
+}
+
+func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if tc.Spec.TiCDC.Replicas == int32(0) {
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if *oldSet.Spec.Replicas != *newSet.Spec.Replicas {
+		klog.Infof("TidbCluster: [%s/%s]'s ticdc is scaling, can not upgrade ticdc", ns, tcName)
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+		return nil
+	}
+
+	tc.Status.TiCDC.Phase = v1alpha1.UpgradePhase
+	if !templateEqual(newSet, oldSet) {
+		return nil
+	}
+
+	if tc.Status.TiCDC.StatefulSet.UpdateRevision == tc.Status.TiCDC.StatefulSet.CurrentRevision {
+		return nil
+	}
+
+	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		// the update strategy is modified manually, let the statefulset controller do the upgrade
+		newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		klog.Warningf("tidbcluster: [%s/%s] ticdc statefulset %s UpdateStrategy has been modified manually", ns, tcName, oldSet.GetName())
+		return nil
+	}
+
+	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	if upgradePaused(tc, label.AnnTiCDCPauseUpgrade) {
+		klog.Infof("tidbcluster: [%s/%s]'s ticdc upgrade is paused by annotation %s", ns, tcName, label.AnnTiCDCPauseUpgrade)
+		return nil
+	}
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
+		i := podOrdinals[_i]
+		podName := ticdcPodName(tcName, i)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return fmt.Errorf("ticdcUpgrader.Upgrade: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s ticdc pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+
+		if revision == tc.Status.TiCDC.StatefulSet.UpdateRevision {
+			if _, exist := tc.Status.TiCDC.Captures[podName]; !exist {
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s ticdc upgraded pod: [%s] is not ready", ns, tcName, podName)
+			}
+			continue
+		}
+
+		if err := gracefulShutdownTiCDC(u.deps, tc, i); err != nil {
+			return err
+		}
+		setUpgradePartition(newSet, i)
+		return nil
+	}
+
+	return nil
+}
+
+// gracefulShutdownTiCDC resigns the owner and drains the tables of the capture before the
+// TiCDC Pod is restarted or removed, so that the changefeeds are not interrupted.
+// It gives up waiting after spec.ticdc.gracefulShutdownTimeout.
+func gracefulShutdownTiCDC(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ordinal int32) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := ticdcPodName(tcName, ordinal)
+
+	if _, exist := tc.Status.TiCDC.Captures[podName]; !exist {
+		// the capture is not running, nothing to drain
+		return nil
+	}
+
+	pod, err := deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("gracefulShutdownTiCDC: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+	beginTimeStr, exist := pod.Annotations[label.AnnTiCDCGracefulShutdownBeginTime]
+	if !exist {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		beginTimeStr = time.Now().Format(time.RFC3339)
+		pod.Annotations[label.AnnTiCDCGracefulShutdownBeginTime] = beginTimeStr
+		if _, err := deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+		klog.Infof("ticdc: set pod %s/%s annotation %s to %s successfully",
+			ns, podName, label.AnnTiCDCGracefulShutdownBeginTime, beginTimeStr)
+	}
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] to time failed.", label.AnnTiCDCGracefulShutdownBeginTime)
+		return nil
+	}
+	timeout := tc.TiCDCGracefulShutdownTimeout()
+	if time.Now().After(beginTime.Add(timeout)) {
+		klog.Infof("ticdc: graceful shutdown timeout (threshold: %v) for pod %s/%s", timeout, ns, podName)
+		return nil
+	}
+
+	resigned, err := deps.CDCControl.ResignOwner(tc, ordinal)
+	if err != nil {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s] failed to resign owner of ticdc pod %s, error: %v", ns, tcName, podName, err)
+	}
+	if !resigned {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s ticdc pod: [%s] is resigning owner", ns, tcName, podName)
+	}
+
+	tableCount, retry, err := deps.CDCControl.DrainCapture(tc, ordinal)
+	if err != nil {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s] failed to drain capture of ticdc pod %s, error: %v", ns, tcName, podName, err)
+	}
+	if retry || tableCount > 0 {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s ticdc pod: [%s] is draining capture, %d table(s) remaining", ns, tcName, podName, tableCount)
+	}
+	klog.Infof("ticdc: capture of pod %s/%s is drained", ns, podName)
+	return nil
+}
+
+type fakeTiCDCUpgrader struct{}
+
+// NewFakeTiCDCUpgrader returns a fake ticdc upgrader
+func NewFakeTiCDCUpgrader() Upgrader {
+	return &fakeTiCDCUpgrader{}
+}
+
+func (u *fakeTiCDCUpgrader) Upgrade(tc *v1alpha1.TidbCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	tc.Status.TiCDC.Phase = v1alpha1.UpgradePhase
+	return nil
+}
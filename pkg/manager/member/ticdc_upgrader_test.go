@@ -0,0 +1,246 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiCDCUpgrader_Upgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		changeFn    func(*v1alpha1.TidbCluster)
+		changePods  func(pods []*corev1.Pod)
+		tableCount  map[string]int
+		owner       string
+		errorExpect bool
+		expectFn    func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		fakeDeps := controller.NewFakeDependencies()
+		upgrader := NewTiCDCUpgrader(fakeDeps)
+		cdcControl := fakeDeps.CDCControl.(*controller.FakeTiCDCControl)
+		podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+
+		tc := newTidbClusterForTiCDCUpgrader()
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		pods := getTiCDCPods()
+		if test.changePods != nil {
+			test.changePods(pods)
+		}
+		for _, pod := range pods {
+			podIndexer.Add(pod)
+		}
+		cdcControl.SetTableCount(test.tableCount)
+		cdcControl.SetOwner(test.owner)
+
+		oldSet := newStatefulSetForTiCDCUpgrader()
+		newSet := oldSet.DeepCopy()
+		SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if test.errorExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		pod, err := fakeDeps.PodLister.Pods(corev1.NamespaceDefault).Get(ticdcPodName(upgradeTcName, 0))
+		g.Expect(err).NotTo(HaveOccurred())
+		test.expectFn(g, tc, newSet, pod)
+	}
+
+	tests := []*testcase{
+		{
+			name: "normal",
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(tc.Status.TiCDC.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+				g.Expect(pod.Annotations).To(HaveKey(label.AnnTiCDCGracefulShutdownBeginTime))
+			},
+		},
+		{
+			name: "capture is draining",
+			tableCount: map[string]int{
+				ticdcPodName(upgradeTcName, 0): 2,
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(pod.Annotations).To(HaveKey(label.AnnTiCDCGracefulShutdownBeginTime))
+			},
+		},
+		{
+			name:        "capture is the owner",
+			owner:       ticdcPodName(upgradeTcName, 0),
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "graceful shutdown timeout",
+			changePods: func(pods []*corev1.Pod) {
+				pods[0].Annotations = map[string]string{
+					label.AnnTiCDCGracefulShutdownBeginTime: time.Now().Add(-time.Hour).Format(time.RFC3339),
+				}
+			},
+			tableCount: map[string]int{
+				ticdcPodName(upgradeTcName, 0): 2,
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name: "capture is not running",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.TiCDC.Captures, ticdcPodName(upgradeTcName, 0))
+			},
+			tableCount: map[string]int{
+				ticdcPodName(upgradeTcName, 0): 2,
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+				g.Expect(pod.Annotations).NotTo(HaveKey(label.AnnTiCDCGracefulShutdownBeginTime))
+			},
+		},
+		{
+			name: "upgraded pod is not ready",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.TiCDC.Captures, ticdcPodName(upgradeTcName, 1))
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pod *corev1.Pod) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func newStatefulSetForTiCDCUpgrader() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.TiCDCMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "ticdc",
+							Image: "ticdc-test-image",
+						},
+					},
+				},
+			},
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: pointer.Int32Ptr(1),
+				},
+			},
+		},
+	}
+}
+
+func newTidbClusterForTiCDCUpgrader() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbCluster",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upgradeTcName,
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID(upgradeTcName),
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiCDC: &v1alpha1.TiCDCSpec{
+				ComponentSpec: v1alpha1.ComponentSpec{
+					Image: "ticdc-test-image",
+				},
+				Replicas: 2,
+			},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			TiCDC: v1alpha1.TiCDCStatus{
+				StatefulSet: &apps.StatefulSetStatus{
+					CurrentReplicas: 1,
+					UpdatedReplicas: 1,
+					CurrentRevision: "1",
+					UpdateRevision:  "2",
+					Replicas:        2,
+				},
+				Captures: map[string]v1alpha1.TiCDCCapture{
+					ticdcPodName(upgradeTcName, 0): {
+						PodName: ticdcPodName(upgradeTcName, 0),
+						ID:      "0",
+					},
+					ticdcPodName(upgradeTcName, 1): {
+						PodName: ticdcPodName(upgradeTcName, 1),
+						ID:      "1",
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTiCDCPods() []*corev1.Pod {
+	lc := label.New().Instance(upgradeInstanceName).TiCDC().Labels()
+	lc[apps.ControllerRevisionHashLabelKey] = "1"
+	lu := label.New().Instance(upgradeInstanceName).TiCDC().Labels()
+	lu[apps.ControllerRevisionHashLabelKey] = "2"
+	pods := []*corev1.Pod{
+		{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ticdcPodName(upgradeTcName, 0),
+				Namespace: corev1.NamespaceDefault,
+				Labels:    lc,
+			},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ticdcPodName(upgradeTcName, 1),
+				Namespace: corev1.NamespaceDefault,
+				Labels:    lu,
+			},
+		},
+	}
+	return pods
+}
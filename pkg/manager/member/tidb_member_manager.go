@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
@@ -32,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
@@ -46,6 +48,19 @@ const (
 	clusterCertPath = "/var/lib/tidb-tls"
 	// serverCertPath is where the tidb-server cert stored (if any)
 	serverCertPath = "/var/lib/tidb-server-tls"
+	// authTokenJWKSPath is where the JWKS for tidb_auth_token stored (if any)
+	authTokenJWKSPath = "/var/lib/tidb-auth-token"
+	// authTokenJWKSKey is the key of the JWKS in the secret
+	authTokenJWKSKey = "jwks.json"
+	// bootstrapSQLPath is where the bootstrap SQL file stored (if any)
+	bootstrapSQLPath = "/etc/tidb-bootstrap"
+	// bootstrapSQLKey is the key of the bootstrap SQL in the secret
+	bootstrapSQLKey = "bootstrap-sql"
+	// tidbZoneLabelKey is the server label used by TiDB for follower reads and local routing
+	tidbZoneLabelKey = "zone"
+	// sqlHealthCheckUserKey and sqlHealthCheckPasswordKey are the keys of the credentials in the sql health check secret
+	sqlHealthCheckUserKey     = "user"
+	sqlHealthCheckPasswordKey = "password"
 	// tlsSecretRootCAKey is the key used in tls secret for the root CA.
 	// When user use self-signed certificates, the root CA must be provided. We
 	// following the same convention used in Kubernetes service token.
@@ -189,6 +204,11 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return nil
 	}
 
+	if tc.TiDBSuspended() {
+		klog.V(4).Infof("tidb of tidb cluster %s/%s is suspended, skip syncing for tidb statefulset", tc.GetNamespace(), tc.GetName())
+		return suspendStatefulSet(m.deps.StatefulSetControl, tc, oldTiDBSet)
+	}
+
 	cm, err := m.syncTiDBConfigMap(tc, oldTiDBSet)
 	if err != nil {
 		return err
@@ -199,6 +219,21 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return err
 	}
 
+	var serverCert []byte
+	var serverCertHash string
+	if tc.Spec.TiDB.TLSClientRotationPolicy() != "" {
+		serverCert, serverCertHash, err = m.getTLSServerCert(tc)
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Spec.TiDB.TLSClientRotationPolicy() == v1alpha1.TLSCertRotationPolicyRollingRestart {
+		if newTiDBSet.Spec.Template.Annotations == nil {
+			newTiDBSet.Spec.Template.Annotations = map[string]string{}
+		}
+		newTiDBSet.Spec.Template.Annotations[label.AnnTiDBServerCertHash] = serverCertHash
+	}
+
 	if setNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newTiDBSet)
 		if err != nil {
@@ -228,7 +263,76 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		}
 	}
 
-	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newTiDBSet, oldTiDBSet)
+	if err := UpdateStatefulSet(m.deps.StatefulSetControl, tc, newTiDBSet, oldTiDBSet); err != nil {
+		return err
+	}
+
+	if tc.Spec.TiDB.TLSClientRotationPolicy() == v1alpha1.TLSCertRotationPolicyReload {
+		return m.reloadTLSServerCert(tc, serverCert, serverCertHash)
+	}
+	return nil
+}
+
+// getTLSServerCert returns the certificate in the TiDB server secret and the hash of the secret
+func (m *tidbMemberManager) getTLSServerCert(tc *v1alpha1.TidbCluster) ([]byte, string, error) {
+	ns := tc.GetNamespace()
+	secretName := tlsClientSecretName(tc)
+	secret, err := m.deps.SecretLister.Secrets(ns).Get(secretName)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to load certificates from secret %s/%s: %v", ns, secretName, err)
+	}
+	hash, err := Sha256Sum(secret.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	return secret.Data[corev1.TLSCertKey], hash, nil
+}
+
+// reloadTLSServerCert reloads the rotated TiDB server certificate on all healthy TiDB servers,
+// the hash of the certificate is recorded in status once all of them serve the new certificate.
+func (m *tidbMemberManager) reloadTLSServerCert(tc *v1alpha1.TidbCluster, cert []byte, hash string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if tc.Status.TiDB.TLSServerCertHash == hash {
+		return nil
+	}
+	if tc.Status.TiDB.Phase != v1alpha1.NormalPhase {
+		// the certificate is reloaded after upgrading or scaling is done
+		return nil
+	}
+	if tc.Spec.TiDB.SQLHealthCheckSecret == nil {
+		msg := fmt.Sprintf("spec.tidb.sqlHealthCheckSecret is required to reload the certificate of tidb cluster %s/%s", ns, tcName)
+		m.deps.Recorder.Event(tc, corev1.EventTypeWarning, FailedReloadTLS, msg)
+		return nil
+	}
+	user, password, err := m.getSQLHealthCheckCredentials(tc)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, ordinal := range tc.TiDBStsDesiredOrdinals(true).List() {
+		podName := tidbPodName(tcName, ordinal)
+		if !tc.Status.TiDB.Members[podName].Health {
+			// the certificate is loaded when the tidb server starts
+			continue
+		}
+		ok, err := m.deps.TiDBControl.ReloadTLS(tc, ordinal, user, password, cert)
+		if err != nil {
+			msg := fmt.Sprintf("failed to reload the certificate of tidb %s/%s: %v", ns, podName, err)
+			m.deps.Recorder.Event(tc, corev1.EventTypeWarning, FailedReloadTLS, msg)
+			return controller.RequeueErrorf("tidbcluster: [%s/%s] %s", ns, tcName, msg)
+		}
+		if !ok {
+			pending = append(pending, podName)
+		}
+	}
+	if len(pending) > 0 {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s] waiting for the rotated certificate to be mounted to tidb %v", ns, tcName, pending)
+	}
+	klog.Infof("tidbcluster: [%s/%s] reload the rotated certificate of tidb successfully", ns, tcName)
+	tc.Status.TiDB.TLSServerCertHash = hash
+	return nil
 }
 
 func (m *tidbMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
@@ -361,6 +465,12 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		config.Set("security.ssl-cert", path.Join(serverCertPath, corev1.TLSCertKey))
 		config.Set("security.ssl-key", path.Join(serverCertPath, corev1.TLSPrivateKeyKey))
 	}
+	if tc.Spec.TiDB.AuthTokenJWKSSecret != nil {
+		config.Set("security.auth-token-jwks", path.Join(authTokenJWKSPath, authTokenJWKSKey))
+	}
+	if tc.Spec.TiDB.BootstrapSQLSecret != nil {
+		config.Set("initialize-sql-file", path.Join(bootstrapSQLPath, bootstrapSQLKey))
+	}
 	confText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
@@ -372,6 +482,7 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		PluginDirectory: "/plugins",
 		PluginList:      strings.Join(plugins, ","),
 		ClusterDomain:   tc.Spec.ClusterDomain,
+		NumactlArgs:     numactlArgs(tc.Spec.TiDB.CPUPinning),
 	}
 
 	if tc.IsHeterogeneous() {
@@ -532,6 +643,16 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Name: "tidb-server-tls", ReadOnly: true, MountPath: serverCertPath,
 		})
 	}
+	if tc.Spec.TiDB.AuthTokenJWKSSecret != nil {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tidb-auth-token", ReadOnly: true, MountPath: authTokenJWKSPath,
+		})
+	}
+	if tc.Spec.TiDB.BootstrapSQLSecret != nil {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tidb-bootstrap-sql", ReadOnly: true, MountPath: bootstrapSQLPath,
+		})
+	}
 
 	vols := []corev1.Volume{
 		annoVolume,
@@ -571,6 +692,48 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			},
 		})
 	}
+	if tc.Spec.TiDB.AuthTokenJWKSSecret != nil {
+		vols = append(vols, corev1.Volume{
+			Name: "tidb-auth-token", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: *tc.Spec.TiDB.AuthTokenJWKSSecret,
+					Items:      []corev1.KeyToPath{{Key: authTokenJWKSKey, Path: authTokenJWKSKey}},
+				},
+			},
+		})
+	}
+	if tc.Spec.TiDB.BootstrapSQLSecret != nil {
+		vols = append(vols, corev1.Volume{
+			Name: "tidb-bootstrap-sql", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: *tc.Spec.TiDB.BootstrapSQLSecret,
+					Items:      []corev1.KeyToPath{{Key: bootstrapSQLKey, Path: bootstrapSQLKey}},
+					// the statements are only executed when the cluster is bootstrapped,
+					// so the secret may be deleted afterwards
+					Optional: pointer.BoolPtr(true),
+				},
+			},
+		})
+	}
+
+	tidbResources := controller.ContainerResource(tc.Spec.TiDB.ResourceRequirements)
+	slowLogTailerResources := controller.ContainerResource(tc.Spec.TiDB.GetSlowLogTailerSpec().ResourceRequirements)
+	if tc.Spec.TiDB.IsCPUPinningEnabled() {
+		// kubelet only allocates exclusive CPUs to the containers of the Pods in the Guaranteed QoS class,
+		// so all containers of the Pod must be Guaranteed
+		fldPath := field.NewPath("spec", "tidb")
+		errs := validation.ValidateGuaranteedResources(tidbResources, true, fldPath)
+		if tc.Spec.TiDB.ShouldSeparateSlowLog() {
+			errs = append(errs, validation.ValidateGuaranteedResources(slowLogTailerResources, false, fldPath.Child("slowLogTailer"))...)
+		}
+		errs = append(errs, validation.ValidateGuaranteedContainers(baseTiDBSpec.InitContainers(), fldPath.Child("initContainers"))...)
+		errs = append(errs, validation.ValidateGuaranteedContainers(baseTiDBSpec.AdditionalContainers(), fldPath.Child("additionalContainers"))...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("resources of tidb don't meet the requirements of CPU pinning for tc %s/%s: %v", ns, tcName, errs.ToAggregate())
+		}
+		tidbResources = guaranteedResources(tidbResources)
+		slowLogTailerResources = guaranteedResources(slowLogTailerResources)
+	}
 
 	sysctls := "sysctl -w"
 	var initContainers []corev1.Container
@@ -598,7 +761,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 					// which means init containers can reserve resources for
 					// initialization that are not used during the life of the Pod.
 					// ref:https://kubernetes.io/docs/concepts/workloads/pods/init-containers/#resources
-					Resources: controller.ContainerResource(tc.Spec.TiDB.ResourceRequirements),
+					Resources: tidbResources,
 				})
 			}
 		}
@@ -660,7 +823,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Name:            v1alpha1.SlowLogTailerMemberType.String(),
 			Image:           tc.HelperImage(),
 			ImagePullPolicy: tc.HelperImagePullPolicy(),
-			Resources:       controller.ContainerResource(tc.Spec.TiDB.GetSlowLogTailerSpec().ResourceRequirements),
+			Resources:       slowLogTailerResources,
 			VolumeMounts:    []corev1.VolumeMount{slowQueryLogVolumeMount},
 			Command: []string{
 				"sh",
@@ -726,13 +889,10 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(tc.Spec.TiDB.ResourceRequirements),
-		Env:          util.AppendEnv(envs, baseTiDBSpec.Env()),
-		ReadinessProbe: &corev1.Probe{
-			Handler:             buildTiDBReadinessProbHandler(tc),
-			InitialDelaySeconds: int32(10),
-		},
+		VolumeMounts:   volMounts,
+		Resources:      tidbResources,
+		Env:            util.AppendEnv(envs, baseTiDBSpec.Env()),
+		ReadinessProbe: buildReadinessProbe(tc.Spec.TiDB.ReadinessProbe, buildTiDBReadinessProbHandler(tc)),
 	}
 	if tc.Spec.TiDB.Lifecycle != nil {
 		c.Lifecycle = tc.Spec.TiDB.Lifecycle
@@ -745,16 +905,21 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	podSpec.Volumes = append(vols, baseTiDBSpec.AdditionalVolumes()...)
 	podSpec.SecurityContext = podSecurityContext
 	podSpec.InitContainers = append(initContainers, baseTiDBSpec.InitContainers()...)
+	if tc.Spec.TiDB.IsCPUPinningEnabled() {
+		setGuaranteedResources(podSpec.InitContainers)
+		setGuaranteedResources(podSpec.Containers)
+	}
 	podSpec.ServiceAccountName = tc.Spec.TiDB.ServiceAccount
 	if podSpec.ServiceAccountName == "" {
 		podSpec.ServiceAccountName = tc.Spec.ServiceAccount
 	}
 
 	if baseTiDBSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = baseTiDBSpec.DnsPolicy()
 	}
 
 	tidbLabel := label.New().Instance(instanceName).TiDB()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseTiDBSpec.TopologySpreadConstraints(), tidbLabel)
 	podAnnotations := CombineAnnotations(controller.AnnProm(10080), baseTiDBSpec.Annotations())
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiDBLabelVal)
 
@@ -763,6 +928,11 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 		return nil, fmt.Errorf("get delete slots number of statefulset %s/%s failed, err:%v", ns, setName, err)
 	}
 
+	podSpec, err = applyPodSpecOverlay(podSpec, tc.Spec.TiDB.PodSpecOverlay)
+	if err != nil {
+		return nil, fmt.Errorf("apply pod spec overlay of statefulset %s/%s failed, err: %v", ns, setName, err)
+	}
+
 	updateStrategy := apps.StatefulSetUpdateStrategy{}
 	if baseTiDBSpec.StatefulSetUpdateStrategy() == apps.OnDeleteStatefulSetStrategyType {
 		updateStrategy.Type = apps.OnDeleteStatefulSetStrategyType
@@ -786,7 +956,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Selector: tidbLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      tidbLabel.Labels(),
+					Labels:      CombineLabels(baseTiDBSpec.Labels(), tidbLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
@@ -813,15 +983,26 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	if err != nil {
 		return err
 	}
-	if tc.TiDBStsDesiredReplicas() != *set.Spec.Replicas {
+	// the statefulset of suspended tidb is scaled to 0 on purpose
+	if !tc.TiDBSuspended() && tc.TiDBStsDesiredReplicas() != *set.Spec.Replicas {
 		tc.Status.TiDB.Phase = v1alpha1.ScalePhase
-	} else if upgrading && tc.Status.TiKV.Phase != v1alpha1.UpgradePhase &&
+	} else if upgrading && tc.Status.TiKV.Phase != v1alpha1.UpgradePhase && tc.Status.TiFlash.Phase != v1alpha1.UpgradePhase &&
 		tc.Status.PD.Phase != v1alpha1.UpgradePhase && tc.Status.Pump.Phase != v1alpha1.UpgradePhase {
 		tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
 	} else {
 		tc.Status.TiDB.Phase = v1alpha1.NormalPhase
 	}
 
+	sqlCheck := tc.Spec.TiDB.SQLHealthCheckSecret != nil
+	var sqlUser, sqlPassword string
+	if sqlCheck {
+		sqlUser, sqlPassword, err = m.getSQLHealthCheckCredentials(tc)
+		if err != nil {
+			klog.Warningf("tidbcluster: [%s/%s] failed to get credentials for sql health check, error: %v", tc.GetNamespace(), tc.GetName(), err)
+			sqlCheck = false
+		}
+	}
+
 	tidbStatus := map[string]v1alpha1.TiDBMember{}
 	for id := range helper.GetPodOrdinals(tc.Status.TiDB.StatefulSet.Replicas, set) {
 		name := fmt.Sprintf("%s-%d", controller.TiDBMemberName(tc.GetName()), id)
@@ -850,6 +1031,22 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 			// Update assiged node if pod exists and is scheduled
 			newTidbMember.NodeName = pod.Spec.NodeName
 		}
+		if health {
+			info, err := m.deps.TiDBControl.GetInfo(tc, int32(id))
+			if err != nil {
+				klog.Warningf("tidbcluster: [%s/%s] failed to get info of tidb %s, error: %v", tc.GetNamespace(), tc.GetName(), name, err)
+			} else if info != nil {
+				newTidbMember.DDLOwner = info.IsOwner
+				newTidbMember.Zone = m.syncTiDBZoneLabel(tc, int32(id), newTidbMember.NodeName, info)
+			}
+			if sqlCheck {
+				if err := m.deps.TiDBControl.CheckSQL(tc, int32(id), sqlUser, sqlPassword); err != nil {
+					klog.Warningf("tidbcluster: [%s/%s] tidb %s failed to execute sql, error: %v", tc.GetNamespace(), tc.GetName(), name, err)
+				} else {
+					newTidbMember.SQLReady = true
+				}
+			}
+		}
 		tidbStatus[name] = newTidbMember
 	}
 	tc.Status.TiDB.Members = tidbStatus
@@ -861,6 +1058,79 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	return nil
 }
 
+// getSQLHealthCheckCredentials returns the user and password in spec.tidb.sqlHealthCheckSecret,
+// the user defaults to root if it is not set in the secret.
+func (m *tidbMemberManager) getSQLHealthCheckCredentials(tc *v1alpha1.TidbCluster) (string, string, error) {
+	ns := tc.GetNamespace()
+	secretName := *tc.Spec.TiDB.SQLHealthCheckSecret
+	secret, err := m.deps.SecretLister.Secrets(ns).Get(secretName)
+	if err != nil {
+		return "", "", err
+	}
+	user := string(secret.Data[sqlHealthCheckUserKey])
+	if user == "" {
+		user = "root"
+	}
+	password, ok := secret.Data[sqlHealthCheckPasswordKey]
+	if !ok {
+		return "", "", fmt.Errorf("key %s is not found in secret %s/%s", sqlHealthCheckPasswordKey, ns, secretName)
+	}
+	return user, string(password), nil
+}
+
+// syncTiDBZoneLabel sets the zone label of the TiDB server from the topology labels of the node
+// hosting it, so that TiDB can serve follower reads and local routing from the same zone.
+// It returns the zone label that is in effect.
+func (m *tidbMemberManager) syncTiDBZoneLabel(tc *v1alpha1.TidbCluster, ordinal int32, nodeName string, info *controller.DBInfo) string {
+	current := info.Labels[tidbZoneLabelKey]
+	// the zone label configured explicitly by users takes precedence
+	if tc.Spec.TiDB.Config != nil && tc.Spec.TiDB.Config.Get("labels."+tidbZoneLabelKey) != nil {
+		return current
+	}
+	if nodeName == "" {
+		return current
+	}
+
+	ns := tc.GetNamespace()
+	podName := tidbPodName(tc.GetName(), ordinal)
+	zone, err := m.getNodeZone(nodeName)
+	if err != nil {
+		klog.Warningf("tidbcluster: [%s/%s] failed to get zone of node %s for tidb %s, error: %v", ns, tc.GetName(), nodeName, podName, err)
+		return current
+	}
+	if zone == "" || zone == current {
+		return current
+	}
+
+	labels := map[string]string{tidbZoneLabelKey: zone}
+	if err := m.deps.TiDBControl.SetServerLabels(tc, ordinal, labels); err != nil {
+		msg := fmt.Sprintf("failed to set labels %v for tidb %s/%s: %v", labels, ns, podName, err)
+		m.deps.Recorder.Event(tc, corev1.EventTypeWarning, FailedSetServerLabels, msg)
+		return current
+	}
+	klog.Infof("pod: [%s/%s] set labels: %v successfully", ns, podName, labels)
+	return zone
+}
+
+// getNodeZone returns the zone of the node, the label "zone" is preferred
+// and then the well-known topology labels of kubernetes.
+func (m *tidbMemberManager) getNodeZone(nodeName string) (string, error) {
+	node, err := m.deps.NodeLister.Get(nodeName)
+	if err != nil {
+		return "", err
+	}
+	ls := node.GetLabels()
+	if zone, found := ls[tidbZoneLabelKey]; found {
+		return zone, nil
+	}
+	for _, nodeLabel := range nodeTopologyLabels[tidbZoneLabelKey] {
+		if zone, found := ls[nodeLabel]; found {
+			return zone, nil
+		}
+	}
+	return "", nil
+}
+
 func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
 	if statefulSetIsUpgrading(set) {
 		return true, nil
@@ -891,7 +1161,16 @@ func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.State
 func buildTiDBReadinessProbHandler(tc *v1alpha1.TidbCluster) corev1.Handler {
 	if tc.Spec.TiDB.ReadinessProbe != nil {
 		if tp := tc.Spec.TiDB.ReadinessProbe.Type; tp != nil {
-			if *tp == v1alpha1.CommandProbeType {
+			// kubelet can not present the client certificate required by the status api with TLS enabled
+			if *tp == v1alpha1.HTTPProbeType && !tc.IsTLSClusterEnabled() {
+				return corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: "/status",
+						Port: intstr.FromInt(10080),
+					},
+				}
+			}
+			if *tp == v1alpha1.CommandProbeType || *tp == v1alpha1.HTTPProbeType {
 				command := buildTiDBProbeCommand(tc)
 				return corev1.Handler{
 					Exec: &corev1.ExecAction{
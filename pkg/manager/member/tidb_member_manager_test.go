@@ -352,6 +352,8 @@ func TestTiDBMemberManagerSyncTidbClusterStatus(t *testing.T) {
 		updateSts   func(*apps.StatefulSet)
 		upgradingFn func(corelisters.PodLister, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
 		healthInfo  map[string]bool
+		sqlReady    map[string]bool
+		secret      *corev1.Secret
 		errExpectFn func(*GomegaWithT, error)
 		tcExpectFn  func(*GomegaWithT, *v1alpha1.TidbCluster)
 	}
@@ -378,7 +380,7 @@ func TestTiDBMemberManagerSyncTidbClusterStatus(t *testing.T) {
 		if test.updateSts != nil {
 			test.updateSts(set)
 		}
-		pmm, _, tidbControl, _ := newFakeTiDBMemberManager()
+		pmm, _, tidbControl, indexers := newFakeTiDBMemberManager()
 
 		if test.upgradingFn != nil {
 			pmm.tidbStatefulSetIsUpgradingFn = test.upgradingFn
@@ -386,6 +388,12 @@ func TestTiDBMemberManagerSyncTidbClusterStatus(t *testing.T) {
 		if test.healthInfo != nil {
 			tidbControl.SetHealth(test.healthInfo)
 		}
+		if test.sqlReady != nil {
+			tidbControl.SetSQLReady(test.sqlReady)
+		}
+		if test.secret != nil {
+			indexers.secret.Add(test.secret)
+		}
 
 		err := pmm.syncTidbClusterStatus(tc, set)
 		if test.errExpectFn != nil {
@@ -544,6 +552,53 @@ func TestTiDBMemberManagerSyncTidbClusterStatus(t *testing.T) {
 				g.Expect(tc.Status.TiDB.Members["test-tidb-2"].LastTransitionTime).NotTo(Equal(now))
 			},
 		},
+		{
+			name: "sql health check",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.SQLHealthCheckSecret = pointer.StringPtr("sql-secret")
+			},
+			healthInfo: map[string]bool{
+				"test-tidb-0": true,
+				"test-tidb-1": true,
+			},
+			sqlReady: map[string]bool{
+				"test-tidb-0": true,
+				"test-tidb-2": true,
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "sql-secret", Namespace: corev1.NamespaceDefault},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			},
+			upgradingFn: func(lister corelisters.PodLister, set *apps.StatefulSet, cluster *v1alpha1.TidbCluster) (bool, error) {
+				return false, nil
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.TiDB.Members["test-tidb-0"].SQLReady).To(BeTrue())
+				g.Expect(tc.Status.TiDB.Members["test-tidb-1"].SQLReady).To(BeFalse())
+				// unhealthy member is not checked
+				g.Expect(tc.Status.TiDB.Members["test-tidb-2"].SQLReady).To(BeFalse())
+			},
+		},
+		{
+			name: "sql health check secret not found",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.SQLHealthCheckSecret = pointer.StringPtr("sql-secret")
+			},
+			healthInfo: map[string]bool{
+				"test-tidb-0": true,
+			},
+			sqlReady: map[string]bool{
+				"test-tidb-0": true,
+			},
+			upgradingFn: func(lister corelisters.PodLister, set *apps.StatefulSet, cluster *v1alpha1.TidbCluster) (bool, error) {
+				return false, nil
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.TiDB.Members["test-tidb-0"].SQLReady).To(BeFalse())
+			},
+		},
 	}
 
 	for i := range tests {
@@ -804,6 +859,193 @@ type fakeIndexers struct {
 	ti     cache.Indexer
 }
 
+func TestTiDBMemberManagerSyncTiDBZoneLabel(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		nodeLabels   map[string]string
+		serverLabels map[string]string
+		config       map[string]interface{}
+		setErr       bool
+		expectZone   string
+		expectLabels map[string]string
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		if test.config != nil {
+			tc.Spec.TiDB.Config = v1alpha1.NewTiDBConfig()
+			for k, v := range test.config {
+				tc.Spec.TiDB.Config.Set(k, v)
+			}
+		}
+		tmm, _, tidbControl, _ := newFakeTiDBMemberManager()
+		if test.setErr {
+			tidbControl.SetServerLabelsError(fmt.Errorf("set labels failed"))
+		}
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-1",
+				Labels: test.nodeLabels,
+			},
+		}
+		tmm.deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+
+		info := &controller.DBInfo{Labels: test.serverLabels}
+		zone := tmm.syncTiDBZoneLabel(tc, 0, "node-1", info)
+		g.Expect(zone).To(Equal(test.expectZone))
+		g.Expect(tidbControl.GetServerLabels(tidbPodName(tc.GetName(), 0))).To(Equal(test.expectLabels))
+	}
+
+	tests := []*testcase{
+		{
+			name:         "set zone from topology label",
+			nodeLabels:   map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+			expectZone:   "zone-a",
+			expectLabels: map[string]string{"zone": "zone-a"},
+		},
+		{
+			name:         "zone label takes precedence",
+			nodeLabels:   map[string]string{"zone": "zone-b", "topology.kubernetes.io/zone": "zone-a"},
+			expectZone:   "zone-b",
+			expectLabels: map[string]string{"zone": "zone-b"},
+		},
+		{
+			name:         "zone already set",
+			nodeLabels:   map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+			serverLabels: map[string]string{"zone": "zone-a"},
+			expectZone:   "zone-a",
+		},
+		{
+			name:       "node has no zone",
+			nodeLabels: map[string]string{"kubernetes.io/hostname": "node-1"},
+			expectZone: "",
+		},
+		{
+			name:         "zone configured by users",
+			nodeLabels:   map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+			serverLabels: map[string]string{"zone": "zone-c"},
+			config:       map[string]interface{}{"labels.zone": "zone-c"},
+			expectZone:   "zone-c",
+		},
+		{
+			name:       "set labels failed",
+			nodeLabels: map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+			setErr:     true,
+			expectZone: "",
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
+
+func TestTiDBMemberManagerReloadTLSServerCert(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		changeFn     func(tc *v1alpha1.TidbCluster)
+		pending      map[string]bool
+		reloadErr    bool
+		errExpect    bool
+		expectReload bool
+		expectHash   string
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		tc := newTidbClusterForTiDB()
+		tc.Spec.TiDB.Replicas = 2
+		tc.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{Enabled: true, RotationPolicy: v1alpha1.TLSCertRotationPolicyReload}
+		tc.Spec.TiDB.SQLHealthCheckSecret = pointer.StringPtr("sql-secret")
+		tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+		tc.Status.TiDB.TLSServerCertHash = "old"
+		tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{
+			tidbPodName(tc.GetName(), 0): {Name: tidbPodName(tc.GetName(), 0), Health: true},
+			tidbPodName(tc.GetName(), 1): {Name: tidbPodName(tc.GetName(), 1), Health: true},
+		}
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		tmm, _, tidbControl, indexers := newFakeTiDBMemberManager()
+		indexers.secret.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sql-secret", Namespace: corev1.NamespaceDefault},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		})
+		tidbControl.SetTLSCertPending(test.pending)
+		if test.reloadErr {
+			tidbControl.SetReloadTLSError(fmt.Errorf("reload tls failed"))
+		}
+
+		err := tmm.reloadTLSServerCert(tc, []byte("cert"), "new")
+		if test.errExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(tidbControl.TLSReloaded(tidbPodName(tc.GetName(), 0))).To(Equal(test.expectReload))
+		g.Expect(tc.Status.TiDB.TLSServerCertHash).To(Equal(test.expectHash))
+	}
+
+	tests := []*testcase{
+		{
+			name:         "reload the rotated certificate",
+			expectReload: true,
+			expectHash:   "new",
+		},
+		{
+			name: "certificate is not rotated",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.TLSServerCertHash = "new"
+			},
+			expectHash: "new",
+		},
+		{
+			name:         "rotated certificate is not mounted yet",
+			pending:      map[string]bool{"test-tidb-1": true},
+			errExpect:    true,
+			expectReload: true,
+			expectHash:   "old",
+		},
+		{
+			name: "unhealthy tidb is skipped",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Members[tidbPodName(tc.GetName(), 0)] = v1alpha1.TiDBMember{Name: tidbPodName(tc.GetName(), 0)}
+			},
+			expectHash: "new",
+		},
+		{
+			name:         "reload failed",
+			reloadErr:    true,
+			errExpect:    true,
+			expectReload: false,
+			expectHash:   "old",
+		},
+		{
+			name: "tidb is upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
+			},
+			expectHash: "old",
+		},
+		{
+			name: "sql credentials are not configured",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.SQLHealthCheckSecret = nil
+			},
+			expectHash: "old",
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
+
 func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSetControl, *controller.FakeTiDBControl, *fakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
 	tmm := &tidbMemberManager{
@@ -1193,6 +1435,67 @@ func TestGetNewTiDBSetForTidbCluster(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "tidb spec authTokenJWKSSecret",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD: &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{
+						AuthTokenJWKSSecret: pointer.StringPtr("jwks"),
+					},
+					TiKV: &v1alpha1.TiKVSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "tidb-auth-token", VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "jwks",
+							Items:      []corev1.KeyToPath{{Key: "jwks.json", Path: "jwks.json"}},
+						},
+					},
+				}))
+				g.Expect(sts.Spec.Template.Spec.Containers[1].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name: "tidb-auth-token", ReadOnly: true, MountPath: "/var/lib/tidb-auth-token",
+				}))
+			},
+		},
+		{
+			name: "tidb spec bootstrapSQLSecret",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD: &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{
+						BootstrapSQLSecret: pointer.StringPtr("bootstrap"),
+					},
+					TiKV: &v1alpha1.TiKVSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "tidb-bootstrap-sql", VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "bootstrap",
+							Items:      []corev1.KeyToPath{{Key: "bootstrap-sql", Path: "bootstrap-sql"}},
+							Optional:   pointer.BoolPtr(true),
+						},
+					},
+				}))
+				g.Expect(sts.Spec.Template.Spec.Containers[1].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name: "tidb-bootstrap-sql", ReadOnly: true, MountPath: "/etc/tidb-bootstrap",
+				}))
+			},
+		},
 		// TODO add more tests
 	}
 
@@ -2290,6 +2593,13 @@ func TestBuildTiDBProbeHandler(t *testing.T) {
 		},
 	}
 
+	httpHandler := corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path: "/status",
+			Port: intstr.FromInt(10080),
+		},
+	}
+
 	tc := &v1alpha1.TidbCluster{
 		Spec: v1alpha1.TidbClusterSpec{
 			TiDB: &v1alpha1.TiDBSpec{},
@@ -2301,21 +2611,35 @@ func TestBuildTiDBProbeHandler(t *testing.T) {
 	g.Expect(get).Should(Equal(defaultHandler))
 
 	// test set command type & not tls
-	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.TiDBProbe{
+	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.Probe{
 		Type: pointer.StringPtr(v1alpha1.CommandProbeType),
 	}
 	get = buildTiDBReadinessProbHandler(tc)
 	g.Expect(get).Should(Equal(execHandler))
 
-	// test command type and tls
+	// test http type & not tls
+	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.Probe{
+		Type: pointer.StringPtr(v1alpha1.HTTPProbeType),
+	}
+	get = buildTiDBReadinessProbHandler(tc)
+	g.Expect(get).Should(Equal(httpHandler))
+
+	// test http type and tls
 	tc.Spec.TLSCluster = &v1alpha1.TLSCluster{
 		Enabled: true,
 	}
 	get = buildTiDBReadinessProbHandler(tc)
 	g.Expect(get).Should(Equal(sslExecHandler))
 
+	// test command type and tls
+	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.Probe{
+		Type: pointer.StringPtr(v1alpha1.CommandProbeType),
+	}
+	get = buildTiDBReadinessProbHandler(tc)
+	g.Expect(get).Should(Equal(sslExecHandler))
+
 	// test tcp type
-	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.TiDBProbe{
+	tc.Spec.TiDB.ReadinessProbe = &v1alpha1.Probe{
 		Type: pointer.StringPtr(v1alpha1.TCPProbeType),
 	}
 	get = buildTiDBReadinessProbHandler(tc)
@@ -19,10 +19,16 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/klog"
 )
 
+const (
+	// MaxResignDDLOwnerCount is the max resign DDL owner count
+	MaxResignDDLOwnerCount = 3
+)
+
 type tidbUpgrader struct {
 	deps *controller.Dependencies
 }
@@ -46,10 +52,10 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 	tcName := tc.GetName()
 
 	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase ||
-		tc.Status.Pump.Phase == v1alpha1.UpgradePhase || tc.TiDBScaling() {
-		klog.Infof("TidbCluster: [%s/%s]'s pd status is %s, tikv status is %s, pump status is %s,"+
+		tc.Status.TiFlash.Phase == v1alpha1.UpgradePhase || tc.Status.Pump.Phase == v1alpha1.UpgradePhase || tc.TiDBScaling() {
+		klog.Infof("TidbCluster: [%s/%s]'s pd status is %s, tikv status is %s, tiflash status is %s, pump status is %s,"+
 			"tidb status is %s, can not upgrade tidb", ns, tcName, tc.Status.PD.Phase, tc.Status.TiKV.Phase,
-			tc.Status.Pump.Phase, tc.Status.TiDB.Phase)
+			tc.Status.TiFlash.Phase, tc.Status.Pump.Phase, tc.Status.TiDB.Phase)
 		_, podSpec, err := GetLastAppliedConfig(oldSet)
 		if err != nil {
 			return err
@@ -78,7 +84,15 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 	}
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	if upgradePaused(tc, label.AnnTiDBPauseUpgrade) {
+		klog.Infof("tidbcluster: [%s/%s]'s tidb upgrade is paused by annotation %s", ns, tcName, label.AnnTiDBPauseUpgrade)
+		return nil
+	}
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	// The partition walk upgrades the Pods in descending order of the ordinals, so the DDL owner
+	// can not be upgraded out of order, it's found once and resigned only right before its Pod
+	// is upgraded.
+	ddlOwner := tidbDDLOwner(tc)
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
 		podName := tidbPodName(tcName, i)
@@ -97,13 +111,41 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 			}
 			continue
 		}
-		return u.upgradeTiDBPod(tc, i, newSet)
+		return u.upgradeTiDBPod(tc, i, newSet, podName == ddlOwner)
 	}
 
 	return nil
 }
 
-func (u *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
+// tidbDDLOwner returns the name of the healthy TiDB Pod which is the DDL owner in the status,
+// it's empty if there is no such Pod
+func tidbDDLOwner(tc *v1alpha1.TidbCluster) string {
+	for name, member := range tc.Status.TiDB.Members {
+		if member.Health && member.DDLOwner {
+			return name
+		}
+	}
+	return ""
+}
+
+func (u *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet, ddlOwner bool) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := tidbPodName(tcName, ordinal)
+	// Hand over the DDL owner before restarting the pod so that running DDL jobs are not interrupted.
+	// Give up after MaxResignDDLOwnerCount retries to avoid blocking the upgrade forever.
+	if ddlOwner {
+		resigned, err := u.deps.TiDBControl.ResignDDLOwner(tc, ordinal)
+		if err != nil && tc.Status.TiDB.ResignDDLOwnerRetryCount < MaxResignDDLOwnerCount {
+			tc.Status.TiDB.ResignDDLOwnerRetryCount++
+			return controller.RequeueErrorf("tidbcluster: [%s/%s] failed to resign ddl owner of tidb pod %s, retry count: %d, error: %v",
+				ns, tcName, podName, tc.Status.TiDB.ResignDDLOwnerRetryCount, err)
+		}
+		if resigned {
+			klog.Infof("tidbcluster: [%s/%s] ddl owner of tidb pod %s resigned", ns, tcName, podName)
+		}
+	}
+	tc.Status.TiDB.ResignDDLOwnerRetryCount = 0
 	setUpgradePartition(newSet, ordinal)
 	return nil
 }
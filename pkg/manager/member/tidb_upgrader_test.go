@@ -14,6 +14,7 @@
 package member
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -35,6 +36,7 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 		name                    string
 		changeFn                func(*v1alpha1.TidbCluster)
 		getLastAppliedConfigErr bool
+		resignDDLOwnerErr       bool
 		errorExpect             bool
 		changeOldSet            func(set *apps.StatefulSet)
 		expectFn                func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet)
@@ -42,11 +44,14 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
-		upgrader, _, podInformer := newTiDBUpgrader()
+		upgrader, tidbControl, podInformer := newTiDBUpgrader()
 		tc := newTidbClusterForTiDBUpgrader()
 		if test.changeFn != nil {
 			test.changeFn(tc)
 		}
+		if test.resignDDLOwnerErr {
+			tidbControl.SetResignDDLOwnerError(fmt.Errorf("resign ddl owner failed"))
+		}
 		pods := getTiDBPods()
 		for _, pod := range pods {
 			podInformer.Informer().GetIndexer().Add(pod)
@@ -141,6 +146,18 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
 			},
 		},
+		{
+			name: "tiflash is upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+			},
+			getLastAppliedConfigErr: false,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
 		{
 			name: "upgrade revision equals current revision",
 			changeFn: func(tc *v1alpha1.TidbCluster) {
@@ -183,6 +200,50 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
 			},
 		},
+		{
+			name: "resign ddl owner failed",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				setTiDBDDLOwner(tc, "upgrader-tidb-0")
+			},
+			getLastAppliedConfigErr: false,
+			resignDDLOwnerErr:       true,
+			errorExpect:             true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.ResignDDLOwnerRetryCount).To(Equal(int32(1)))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "resign ddl owner failed and retry count exceeds the limit",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Status.TiDB.ResignDDLOwnerRetryCount = MaxResignDDLOwnerCount
+				setTiDBDDLOwner(tc, "upgrader-tidb-0")
+			},
+			getLastAppliedConfigErr: false,
+			resignDDLOwnerErr:       true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.ResignDDLOwnerRetryCount).To(Equal(int32(0)))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name: "not resign ddl owner for the pod which is not the ddl owner",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				setTiDBDDLOwner(tc, "upgrader-tidb-1")
+			},
+			getLastAppliedConfigErr: false,
+			resignDDLOwnerErr:       true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.ResignDDLOwnerRetryCount).To(Equal(int32(0)))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -316,3 +377,9 @@ func getTiDBPods() []*corev1.Pod {
 	}
 	return pods
 }
+
+func setTiDBDDLOwner(tc *v1alpha1.TidbCluster, podName string) {
+	member := tc.Status.TiDB.Members[podName]
+	member.DDLOwner = true
+	tc.Status.TiDB.Members[podName] = member
+}
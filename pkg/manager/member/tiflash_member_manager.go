@@ -171,6 +171,12 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
+	if tc.TiFlashSuspended() {
+		klog.V(4).Infof("tiflash of tidb cluster %s/%s is suspended, skip syncing for tiflash statefulset", tc.GetNamespace(), tc.GetName())
+		resetTiFlashStoresForSuspension(tc)
+		return suspendStatefulSet(m.deps.StatefulSetControl, tc, oldSet)
+	}
+
 	cm, err := m.syncConfigMap(tc, oldSet)
 	if err != nil {
 		return err
@@ -233,6 +239,21 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newSet, oldSet)
 }
 
+// resetTiFlashStoresForSuspension clears the failure stores of the suspended TiFlash and keeps
+// refreshing the transition time of its stores, which are down on purpose, so that no failover
+// is triggered until the stores have been down for the failover period after resuming.
+func resetTiFlashStoresForSuspension(tc *v1alpha1.TidbCluster) {
+	if len(tc.Status.TiFlash.FailureStores) > 0 {
+		klog.Infof("tiflash of tidb cluster %s/%s is suspended, clear FailureStores", tc.GetNamespace(), tc.GetName())
+		tc.Status.TiFlash.FailureStores = nil
+	}
+	now := metav1.Now()
+	for id, store := range tc.Status.TiFlash.Stores {
+		store.LastTransitionTime = now
+		tc.Status.TiFlash.Stores[id] = store
+	}
+}
+
 func (m *tiflashMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
 	newCm, err := getTiFlashConfigMap(tc)
 	if err != nil {
@@ -250,6 +271,15 @@ func (m *tiflashMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps
 	if err != nil {
 		return nil, err
 	}
+	if tc.TiFlashConfigReloaderEnabled() {
+		// only the changes of the static items trigger a rolling update, the reloadable
+		// sections are updated in place and reloaded by TiFlash
+		digest, err := tiflashStaticConfigDigest(tc)
+		if err != nil {
+			return nil, err
+		}
+		newCm.Name = fmt.Sprintf("%s-%s", controller.TiFlashMemberName(tc.Name), digest[0:7])
+	}
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(tc, newCm)
 }
 
@@ -462,7 +492,7 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "tiflash",
-				ContainerPort: int32(3930),
+				ContainerPort: v1alpha1.DefaultTiFlashProxyPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 			{
@@ -491,12 +521,14 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(tc.Spec.TiFlash.ResourceRequirements),
+		VolumeMounts:   volMounts,
+		Resources:      controller.ContainerResource(tc.Spec.TiFlash.ResourceRequirements),
+		ReadinessProbe: buildTCPReadinessProbe(tc.Spec.TiFlash.ReadinessProbe, v1alpha1.DefaultTiFlashProxyPort),
 	}
 	podSpec := baseTiFlashSpec.BuildPodSpec()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseTiFlashSpec.TopologySpreadConstraints(), tiflashLabel)
 	if baseTiFlashSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = baseTiFlashSpec.DnsPolicy()
 		env = append(env, corev1.EnvVar{
 			Name: "POD_NAME",
 			ValueFrom: &corev1.EnvVarSource{
@@ -514,6 +546,10 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 	if err != nil {
 		return nil, err
 	}
+	if tc.TiFlashConfigReloaderEnabled() {
+		// the reloadable sections of the config are updated in place
+		containers = append(containers, buildTiFlashConfigReloaderContainer(tc))
+	}
 	podSpec.Containers = append([]corev1.Container{tiflashContainer}, containers...)
 	podSpec.Containers = append(podSpec.Containers, baseTiFlashSpec.AdditionalContainers()...)
 	podSpec.ServiceAccountName = tc.Spec.TiFlash.ServiceAccount
@@ -544,7 +580,7 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 			Selector: tiflashLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      tiflashLabel.Labels(),
+					Labels:      CombineLabels(baseTiFlashSpec.Labels(), tiflashLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
@@ -624,7 +660,8 @@ func (m *tiflashMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 	if err != nil {
 		return err
 	}
-	if tc.TiFlashStsDesiredReplicas() != *set.Spec.Replicas {
+	// the statefulset of suspended tiflash is scaled to 0 on purpose
+	if !tc.TiFlashSuspended() && tc.TiFlashStsDesiredReplicas() != *set.Spec.Replicas {
 		tc.Status.TiFlash.Phase = v1alpha1.ScalePhase
 	} else if upgrading {
 		tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
@@ -723,6 +760,7 @@ func (m *tiflashMemberManager) getTiFlashStore(store *pdapi.StoreInfo) *v1alpha1
 		PodName:           podName,
 		IP:                ip,
 		LeaderCount:       int32(store.Status.LeaderCount),
+		RegionCount:       int32(store.Status.RegionCount),
 		State:             store.Store.StateName,
 		LastHeartbeatTime: metav1.Time{Time: store.Status.LastHeartbeatTS},
 	}
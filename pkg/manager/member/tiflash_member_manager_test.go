@@ -163,6 +163,39 @@ func TestTiFlashMemberManagerSyncCreate(t *testing.T) {
 	}
 }
 
+func TestTiFlashMemberManagerSuspendAndResume(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiflash()
+	tc.Spec.TiFlash.Suspend = true
+	tc.Spec.TiFlash.MaxFailoverCount = pointer.Int32Ptr(3)
+	downSince := metav1.NewTime(time.Now().Add(-time.Hour))
+	tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: controller.TiFlashMemberName(tc.GetName()) + "-0", State: v1alpha1.TiKVStateDown, LastTransitionTime: downSince},
+		"2": {ID: "2", PodName: controller.TiFlashMemberName(tc.GetName()) + "-1", State: v1alpha1.TiKVStateDown, LastTransitionTime: downSince},
+	}
+	// recorded when the stores go down during the suspension
+	tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+		"1": {PodName: controller.TiFlashMemberName(tc.GetName()) + "-0", StoreID: "1", CreatedAt: downSince},
+	}
+
+	tfmm, _, _, _, _, _ := newFakeTiFlashMemberManager(tc)
+	tfmm.failover = NewTiFlashFailover(tfmm.deps)
+
+	err := tfmm.syncStatefulSet(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiFlash.FailureStores).To(BeEmpty())
+
+	// the stores are still down right after resuming
+	tc.Spec.TiFlash.Suspend = false
+	err = tfmm.failover.Failover(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiFlash.FailureStores).To(BeEmpty())
+	for _, store := range tc.Status.TiFlash.Stores {
+		g.Expect(store.LastTransitionTime.After(downSince.Time)).To(BeTrue())
+	}
+}
+
 func TestTiFlashMemberManagerTiFlashStatefulSetIsUpgrading(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -1664,6 +1697,89 @@ func TestGetNewTiFlashSetForTidbCluster(t *testing.T) {
 				}), "Expected the CAPACITY of tiflash is properly set")
 			},
 		},
+		{
+			name: "tiflash config reloader is enabled",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					ConfigUpdateStrategy: v1alpha1.ConfigUpdateStrategyRollingUpdate,
+					TiFlash: &v1alpha1.TiFlashSpec{
+						StorageClaims: []v1alpha1.StorageClaim{
+							{
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("10Gi"),
+									},
+								},
+							},
+						},
+						ConfigReloader: &v1alpha1.TiFlashConfigReloaderSpec{
+							ResourceRequirements: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("100m"),
+								},
+							},
+							Enabled: true,
+						},
+					},
+					TiDB: &v1alpha1.TiDBSpec{},
+					PD:   &v1alpha1.PDSpec{},
+					TiKV: &v1alpha1.TiKVSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				nameToContainer := MapContainers(&sts.Spec.Template.Spec)
+				g.Expect(nameToContainer).To(HaveKey("config-reloader"))
+				g.Expect(nameToContainer["config-reloader"].Resources).To(Equal(corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("100m"),
+					},
+				}))
+			},
+		},
+		{
+			name: "tiflash config reloader is disabled",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					ConfigUpdateStrategy: v1alpha1.ConfigUpdateStrategyRollingUpdate,
+					TiFlash: &v1alpha1.TiFlashSpec{
+						StorageClaims: []v1alpha1.StorageClaim{
+							{
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("10Gi"),
+									},
+								},
+							},
+						},
+						ConfigReloader: &v1alpha1.TiFlashConfigReloaderSpec{
+							ResourceRequirements: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("100m"),
+								},
+							},
+							Enabled: false,
+						},
+					},
+					TiDB: &v1alpha1.TiDBSpec{},
+					PD:   &v1alpha1.PDSpec{},
+					TiKV: &v1alpha1.TiKVSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				nameToContainer := MapContainers(&sts.Spec.Template.Spec)
+				g.Expect(nameToContainer).NotTo(HaveKey("config-reloader"))
+			},
+		},
 		// TODO add more tests
 	}
 
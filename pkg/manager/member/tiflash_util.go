@@ -30,6 +30,65 @@ const (
 	defaultServerLog  = "/data0/logs/server.log"
 )
 
+// tiflashReloadableConfigKeys are the sections of the TiFlash config which are reloaded
+// by TiFlash when the config file changes, the others take effect only after a restart.
+var tiflashReloadableConfigKeys = []string{"profiles", "users", "quotas"}
+
+// tiflashStaticConfigDigest returns the digest of the TiFlash config and the proxy config
+// excluding the reloadable sections, so that only the changes of the static items
+// result in a new ConfigMap and a rolling update.
+func tiflashStaticConfigDigest(tc *v1alpha1.TidbCluster) (string, error) {
+	config := getTiFlashConfig(tc)
+	for _, k := range tiflashReloadableConfigKeys {
+		config.Common.Del(k)
+	}
+	configText, err := config.Common.MarshalTOML()
+	if err != nil {
+		return "", err
+	}
+	proxyText, err := config.Proxy.MarshalTOML()
+	if err != nil {
+		return "", err
+	}
+	return Sha256Sum(map[string]string{
+		"config_templ.toml": string(configText),
+		"proxy_templ.toml":  string(proxyText),
+	})
+}
+
+// buildTiFlashConfigReloaderContainer returns the sidecar which renders the TiFlash config file again
+// when the ConfigMap is updated in place, TiFlash then reloads the reloadable sections from it.
+func buildTiFlashConfigReloaderContainer(tc *v1alpha1.TidbCluster) corev1.Container {
+	return corev1.Container{
+		Name:            "config-reloader",
+		Image:           tc.HelperImage(),
+		ImagePullPolicy: tc.HelperImagePullPolicy(),
+		Resources:       controller.ContainerResource(tc.Spec.TiFlash.ConfigReloader.ResourceRequirements),
+		Command: []string{
+			"sh",
+			"-c",
+			"set -e;ordinal=`echo ${POD_NAME} | awk -F- '{print $NF}'`;" +
+				"while true; do sed s/POD_NUM/${ordinal}/g /etc/tiflash/config_templ.toml > /data0/config.toml.tmp;" +
+				"if ! cmp -s /data0/config.toml.tmp /data0/config.toml; then mv /data0/config.toml.tmp /data0/config.toml; echo \"config.toml is updated\"; fi;" +
+				"sleep 10; done",
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data0", MountPath: "/data0"},
+			{Name: "config", ReadOnly: true, MountPath: "/etc/tiflash"},
+		},
+	}
+}
+
 func buildTiFlashSidecarContainers(tc *v1alpha1.TidbCluster) ([]corev1.Container, error) {
 	spec := tc.Spec.TiFlash
 	config := spec.Config.DeepCopy()
@@ -847,3 +847,62 @@ func mustFromOldConfig(old *v1alpha1.TiFlashConfig) *v1alpha1.TiFlashConfigWrape
 
 	return config
 }
+
+func TestTiFlashStaticConfigDigest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		changeFn     func(config *v1alpha1.TiFlashConfigWraper)
+		expectChange bool
+	}
+
+	tests := []*testcase{
+		{
+			name: "reloadable profiles changed",
+			changeFn: func(config *v1alpha1.TiFlashConfigWraper) {
+				config.Common.Set("profiles.default.max_memory_usage", 20000000000)
+			},
+			expectChange: false,
+		},
+		{
+			name: "reloadable users and quotas changed",
+			changeFn: func(config *v1alpha1.TiFlashConfigWraper) {
+				config.Common.Set("users.readonly.profile", "readonly")
+				config.Common.Set("quotas.default.interval.duration", 3600)
+			},
+			expectChange: false,
+		},
+		{
+			name: "static config changed",
+			changeFn: func(config *v1alpha1.TiFlashConfigWraper) {
+				config.Common.Set("logger.level", "debug")
+			},
+			expectChange: true,
+		},
+		{
+			name: "proxy config changed",
+			changeFn: func(config *v1alpha1.TiFlashConfigWraper) {
+				config.Proxy.Set("server.grpc-concurrency", 8)
+			},
+			expectChange: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbCluster()
+		tc.Spec.TiFlash.Config = v1alpha1.NewTiFlashConfig()
+		digest, err := tiflashStaticConfigDigest(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		test.changeFn(tc.Spec.TiFlash.Config)
+		newDigest, err := tiflashStaticConfigDigest(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		if test.expectChange {
+			g.Expect(newDigest).NotTo(Equal(digest))
+		} else {
+			g.Expect(newDigest).To(Equal(digest))
+		}
+	}
+}
@@ -21,10 +21,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
@@ -34,8 +36,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
 	"k8s.io/utils/pointer"
@@ -50,8 +55,20 @@ const (
 
 	//find a better way to manage store only managed by tikv in Operator
 	tikvStoreLimitPattern = `%s-tikv-\d+\.%s-tikv-peer\.%s\.svc%s\:\d+`
+
+	// tikvSlowStoreGracePeriod is how long a store must stay slow before its leaders are evicted,
+	// so a transient spike of the slow score does not trigger leader eviction
+	tikvSlowStoreGracePeriod = 5 * time.Minute
 )
 
+// nodeTopologyLabels maps the well-known store labels to the node labels carrying
+// the same topology information, they are used if the node has no such store label.
+var nodeTopologyLabels = map[string][]string{
+	"host":   {corev1.LabelHostname},
+	"zone":   {"topology.kubernetes.io/zone", corev1.LabelZoneFailureDomain},
+	"region": {"topology.kubernetes.io/region", corev1.LabelZoneRegion},
+}
+
 // tikvMemberManager implements manager.Manager.
 type tikvMemberManager struct {
 	deps                     *controller.Dependencies
@@ -110,6 +127,11 @@ func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 			return err
 		}
 	}
+	// at most one tikv store is disrupted at a time to keep the majority of region replicas
+	tikvSelector := label.New().Instance(tc.GetInstanceName()).TiKV()
+	if err := syncPodDisruptionBudget(m.deps, tc, controller.TiKVMemberName(tc.GetName()), tikvSelector, 1); err != nil {
+		return err
+	}
 	return m.syncStatefulSetForTidbCluster(tc)
 }
 
@@ -178,6 +200,22 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return nil
 	}
 
+	if err := m.cleanupOrphanEvictLeaderSchedulers(tc); err != nil {
+		return err
+	}
+
+	if err := m.evictLeadersOfSlowStores(tc); err != nil {
+		return err
+	}
+
+	if err := m.evictLeadersForMaintenance(tc); err != nil {
+		return err
+	}
+
+	if err := m.removeTombstoneStores(tc); err != nil {
+		return err
+	}
+
 	cm, err := m.syncTiKVConfigMap(tc, oldSet)
 	if err != nil {
 		return err
@@ -214,6 +252,10 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return err
 	}
 
+	if err := m.setStoreWeightsForTiKV(tc); err != nil {
+		return err
+	}
+
 	// Scaling takes precedence over upgrading because:
 	// - if a store fails in the upgrading, users may want to delete it or add
 	//   new replicas
@@ -381,6 +423,25 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	storageVolMounts, additionalPVCs := util.BuildStorageVolumeAndVolumeMount(tc.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageClassName, v1alpha1.TiKVMemberType)
 	volMounts = append(volMounts, storageVolMounts...)
 
+	tikvResources := controller.ContainerResource(tc.Spec.TiKV.ResourceRequirements)
+	logTailerResources := controller.ContainerResource(tc.Spec.TiKV.GetLogTailerSpec().ResourceRequirements)
+	if tc.Spec.TiKV.IsCPUPinningEnabled() {
+		// kubelet only allocates exclusive CPUs to the containers of the Pods in the Guaranteed QoS class,
+		// so all containers of the Pod must be Guaranteed
+		fldPath := field.NewPath("spec", "tikv")
+		errs := validation.ValidateGuaranteedResources(tikvResources, true, fldPath)
+		if tc.Spec.TiKV.ShouldSeparateRocksDBLog() || tc.Spec.TiKV.ShouldSeparateRaftLog() {
+			errs = append(errs, validation.ValidateGuaranteedResources(logTailerResources, false, fldPath.Child("logTailer"))...)
+		}
+		errs = append(errs, validation.ValidateGuaranteedContainers(baseTiKVSpec.InitContainers(), fldPath.Child("initContainers"))...)
+		errs = append(errs, validation.ValidateGuaranteedContainers(baseTiKVSpec.AdditionalContainers(), fldPath.Child("additionalContainers"))...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("resources of tikv don't meet the requirements of CPU pinning for tc %s/%s: %v", ns, tcName, errs.ToAggregate())
+		}
+		tikvResources = guaranteedResources(tikvResources)
+		logTailerResources = guaranteedResources(logTailerResources)
+	}
+
 	sysctls := "sysctl -w"
 	var initContainers []corev1.Container
 	if baseTiKVSpec.Annotations() != nil {
@@ -407,7 +468,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 					// which means init containers can reserve resources for
 					// initialization that are not used during the life of the Pod.
 					// ref:https://kubernetes.io/docs/concepts/workloads/pods/init-containers/#resources
-					Resources: controller.ContainerResource(tc.Spec.TiKV.ResourceRequirements),
+					Resources: tikvResources,
 				})
 			}
 		}
@@ -445,7 +506,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Name:            v1alpha1.RocksDBLogTailerMemberType.String(),
 			Image:           tc.HelperImage(),
 			ImagePullPolicy: tc.HelperImagePullPolicy(),
-			Resources:       controller.ContainerResource(tc.Spec.TiKV.GetLogTailerSpec().ResourceRequirements),
+			Resources:       logTailerResources,
 			VolumeMounts:    []corev1.VolumeMount{tikvDataVol},
 			Command: []string{
 				"sh",
@@ -461,7 +522,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Name:            v1alpha1.RaftLogTailerMemberType.String(),
 			Image:           tc.HelperImage(),
 			ImagePullPolicy: tc.HelperImagePullPolicy(),
-			Resources:       controller.ContainerResource(tc.Spec.TiKV.GetLogTailerSpec().ResourceRequirements),
+			Resources:       logTailerResources,
 			VolumeMounts:    []corev1.VolumeMount{tikvDataVol},
 			Command: []string{
 				"sh",
@@ -508,12 +569,13 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "server",
-				ContainerPort: int32(20160),
+				ContainerPort: v1alpha1.DefaultTiKVServerPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		VolumeMounts: volMounts,
-		Resources:    controller.ContainerResource(tc.Spec.TiKV.ResourceRequirements),
+		VolumeMounts:   volMounts,
+		Resources:      tikvResources,
+		ReadinessProbe: buildTCPReadinessProbe(tc.Spec.TiKV.ReadinessProbe, v1alpha1.DefaultTiKVServerPort),
 	}
 
 	if tc.Spec.TiKV.EnableNamedStatusPort {
@@ -527,8 +589,9 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	}
 
 	podSpec := baseTiKVSpec.BuildPodSpec()
+	podSpec.TopologySpreadConstraints = getTopologySpreadConstraints(baseTiKVSpec.TopologySpreadConstraints(), tikvLabel)
 	if baseTiKVSpec.HostNetwork() {
-		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		podSpec.DNSPolicy = baseTiKVSpec.DnsPolicy()
 		env = append(env, corev1.EnvVar{
 			Name: "POD_NAME",
 			ValueFrom: &corev1.EnvVarSource{
@@ -545,11 +608,20 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	podSpec.SecurityContext = podSecurityContext
 	podSpec.InitContainers = append(initContainers, baseTiKVSpec.InitContainers()...)
 	podSpec.Containers = append(containers, baseTiKVSpec.AdditionalContainers()...)
+	if tc.Spec.TiKV.IsCPUPinningEnabled() {
+		setGuaranteedResources(podSpec.InitContainers)
+		setGuaranteedResources(podSpec.Containers)
+	}
 	podSpec.ServiceAccountName = tc.Spec.TiKV.ServiceAccount
 	if podSpec.ServiceAccountName == "" {
 		podSpec.ServiceAccountName = tc.Spec.ServiceAccount
 	}
 
+	podSpec, err = applyPodSpecOverlay(podSpec, tc.Spec.TiKV.PodSpecOverlay)
+	if err != nil {
+		return nil, fmt.Errorf("apply pod spec overlay of statefulset %s/%s failed, err: %v", ns, setName, err)
+	}
+
 	updateStrategy := apps.StatefulSetUpdateStrategy{}
 	if baseTiKVSpec.StatefulSetUpdateStrategy() == apps.OnDeleteStatefulSetStrategyType {
 		updateStrategy.Type = apps.OnDeleteStatefulSetStrategyType
@@ -573,7 +645,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Selector: tikvLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      tikvLabel.Labels(),
+					Labels:      CombineLabels(baseTiKVSpec.Labels(), tikvLabel.Labels()),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
@@ -642,6 +714,7 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		EnableAdvertiseStatusAddr: false,
 		DataDir:                   filepath.Join(tikvDataVolumeMountPath, tc.Spec.TiKV.DataSubDir),
 		ClusterDomain:             tc.Spec.ClusterDomain,
+		NumactlArgs:               numactlArgs(tc.Spec.TiKV.CPUPinning),
 	}
 	if tc.Spec.EnableDynamicConfiguration != nil && *tc.Spec.EnableDynamicConfiguration {
 		scriptModel.AdvertiseStatusAddr = "${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc" + controller.FormatClusterDomain(tc.Spec.ClusterDomain)
@@ -692,6 +765,11 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		}
 	}
 
+	// Stay in UpgradePhase until the post-upgrade hook of the update revision completes.
+	if !upgrading && tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
+		upgrading = !upgradeHookCompleted(tc.TiKVPostUpgradeHook(), tc.Status.TiKV.PostUpgradeHookRevision, set.Status.UpdateRevision)
+	}
+
 	// Scaling takes precedence over upgrading.
 	if tc.TiKVStsDesiredReplicas() != *set.Spec.Replicas {
 		tc.Status.TiKV.Phase = v1alpha1.ScalePhase
@@ -747,6 +825,15 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 			status.LastTransitionTime = oldStore.LastTransitionTime
 		}
 
+		if status.IsSlow() {
+			if exist && oldStore.SlowSince != nil {
+				status.SlowSince = oldStore.SlowSince
+			} else {
+				now := metav1.Now()
+				status.SlowSince = &now
+			}
+		}
+
 		// In theory, the external tikv can join the cluster, and the operator would only manage the internal tikv.
 		// So we check the store owner to make sure it.
 		if store.Store != nil {
@@ -775,6 +862,20 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		tombstoneStores[status.ID] = *status
 	}
 
+	// The evict leader schedulers are only shown in the status, so failing to list them,
+	// e.g. PD does not support the API, does not block the sync of the status.
+	evictLeaderSchedulers, err := pdCli.GetEvictLeaderSchedulers()
+	if err != nil {
+		klog.Warningf("tikv: failed to get evict leader schedulers of %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	for _, scheduler := range evictLeaderSchedulers {
+		storeID := scheduler[strings.LastIndex(scheduler, "-")+1:]
+		if store, ok := stores[storeID]; ok {
+			store.EvictLeaderScheduler = scheduler
+			stores[storeID] = store
+		}
+	}
+
 	tc.Status.TiKV.Synced = true
 	tc.Status.TiKV.Stores = stores
 	tc.Status.TiKV.PeerStores = peerStores
@@ -801,11 +902,234 @@ func getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.TiKVStore {
 		PodName:           podName,
 		IP:                ip,
 		LeaderCount:       int32(store.Status.LeaderCount),
+		RegionCount:       int32(store.Status.RegionCount),
+		SlowScore:         int32(store.Status.SlowScore),
+		DiskUsagePercent:  storeDiskUsagePercent(store.Status),
 		State:             store.Store.StateName,
 		LastHeartbeatTime: metav1.Time{Time: store.Status.LastHeartbeatTS},
 	}
 }
 
+// storeDiskUsagePercent returns the used percent of the store capacity
+func storeDiskUsagePercent(status *pdapi.StoreStatus) int32 {
+	if status.Capacity == 0 || status.Available > status.Capacity {
+		return 0
+	}
+	return int32((status.Capacity - status.Available) * 100 / status.Capacity)
+}
+
+// cleanupOrphanEvictLeaderSchedulers removes the evict leader schedulers left
+// behind by an interrupted upgrade, e.g. the pod was deleted or the operator
+// restarted in the middle of leader eviction. Leaders are only evicted by the
+// operator during upgrading, for slow stores and for Pods under maintenance, so
+// any evict leader scheduler added by the operator is orphaned when TiKV is in
+// normal phase and the store is neither slow nor under maintenance. The schedulers
+// not added by the operator, e.g. by pd-ctl, are kept.
+func (m *tikvMemberManager) cleanupOrphanEvictLeaderSchedulers(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiKV.Phase != v1alpha1.NormalPhase {
+		return nil
+	}
+
+	slowStoreProtection := tc.TiKVSlowStoreProtectionEnabled()
+	for _, id := range sets.StringKeySet(tc.Status.TiKV.EvictLeaderStores).List() {
+		store, exist := tc.Status.TiKV.Stores[id]
+		if exist {
+			if slowStoreProtection && store.IsSlow() {
+				continue
+			}
+			maintaining, err := m.storeUnderMaintenance(tc, store)
+			if err != nil {
+				return err
+			}
+			if maintaining {
+				continue
+			}
+		}
+		storeID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := endEvictLeaderbyStoreID(m.deps, tc, storeID); err != nil {
+			return err
+		}
+		m.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "EvictLeaderSchedulerRemoved", "orphaned evict leader scheduler of tikv store %s is removed", id)
+	}
+	return nil
+}
+
+// evictLeadersOfSlowStores evicts the region leaders of a store reported as slow
+// by PD for tikvSlowStoreGracePeriod when slow store protection is enabled, the
+// evict leader scheduler is removed by cleanupOrphanEvictLeaderSchedulers once the
+// store recovers. To keep enough stores serving leaders, the leaders of at most one
+// store are evicted at a time, and none are evicted while any store has an evict
+// leader scheduler.
+func (m *tikvMemberManager) evictLeadersOfSlowStores(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiKV.Phase != v1alpha1.NormalPhase || !tc.TiKVSlowStoreProtectionEnabled() {
+		return nil
+	}
+
+	if len(tc.Status.TiKV.EvictLeaderStores) > 0 {
+		klog.V(4).Infof("tikv: leaders of stores %v of %s/%s are being evicted, skip slow stores", sets.StringKeySet(tc.Status.TiKV.EvictLeaderStores).List(), tc.Namespace, tc.Name)
+		return nil
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.EvictLeaderScheduler != "" {
+			klog.V(4).Infof("tikv: leaders of store %s of %s/%s are being evicted, skip slow stores", store.ID, tc.Namespace, tc.Name)
+			return nil
+		}
+	}
+
+	for _, store := range tc.TiKVSlowStores() {
+		if store.SlowSince == nil || time.Since(store.SlowSince.Time) < tikvSlowStoreGracePeriod {
+			continue
+		}
+		evicted, err := m.beginEvictLeaderOfStore(tc, store)
+		if err != nil {
+			klog.Errorf("tikv: failed to begin evict leader of slow store %s, %s/%s, %v", store.ID, tc.Namespace, store.PodName, err)
+			return err
+		}
+		if !evicted {
+			// the store has an evict leader scheduler not shown in the status yet
+			return nil
+		}
+		klog.Infof("tikv: begin evict leader of slow store %s, %s/%s successfully", store.ID, tc.Namespace, store.PodName)
+		m.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "SlowStoreDetected", "tikv pod %s/%s is slow with score %d, its leaders are evicted", tc.Namespace, store.PodName, store.SlowScore)
+		return nil
+	}
+	return nil
+}
+
+// evictLeadersForMaintenance evicts the region leaders of the stores whose Pods
+// are annotated with tidb.pingcap.com/evict-leader, so the nodes can be drained
+// without pd-ctl. The evict leader schedulers are removed by
+// cleanupOrphanEvictLeaderSchedulers once the annotation is removed.
+func (m *tikvMemberManager) evictLeadersForMaintenance(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiKV.Phase != v1alpha1.NormalPhase {
+		return nil
+	}
+
+	for _, id := range sets.StringKeySet(tc.Status.TiKV.Stores).List() {
+		store := tc.Status.TiKV.Stores[id]
+		maintaining, err := m.storeUnderMaintenance(tc, store)
+		if err != nil {
+			return err
+		}
+		if !maintaining {
+			continue
+		}
+		evicted, err := m.beginEvictLeaderOfStore(tc, store)
+		if err != nil {
+			klog.Errorf("tikv: failed to begin evict leader of store %s under maintenance, %s/%s, %v", store.ID, tc.Namespace, store.PodName, err)
+			return err
+		}
+		if !evicted {
+			continue
+		}
+		klog.Infof("tikv: begin evict leader of store %s under maintenance, %s/%s successfully", store.ID, tc.Namespace, store.PodName)
+		m.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "EvictLeaderForMaintenance", "leaders of tikv pod %s/%s are evicted for maintenance", tc.Namespace, store.PodName)
+	}
+	return nil
+}
+
+// beginEvictLeaderOfStore adds the evict leader scheduler of the store unless it already exists,
+// it returns whether the scheduler is added. The existing scheduler is queried from PD, since it
+// may be added by others and the schedulers in the status may be out of date.
+func (m *tikvMemberManager) beginEvictLeaderOfStore(tc *v1alpha1.TidbCluster, store v1alpha1.TiKVStore) (bool, error) {
+	if _, exist := tc.Status.TiKV.EvictLeaderStores[store.ID]; exist {
+		return false, nil
+	}
+	storeID, err := strconv.ParseUint(store.ID, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	scheduler, err := controller.GetPDClient(m.deps.PDControl, tc).GetEvictLeaderScheduler(storeID)
+	if err != nil {
+		return false, err
+	}
+	if scheduler != "" {
+		return false, nil
+	}
+	if err := beginEvictLeaderbyStoreID(m.deps, tc, storeID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// storeUnderMaintenance returns whether the Pod of the store is annotated with
+// tidb.pingcap.com/evict-leader
+func (m *tikvMemberManager) storeUnderMaintenance(tc *v1alpha1.TidbCluster, store v1alpha1.TiKVStore) (bool, error) {
+	ns := tc.GetNamespace()
+	pod, err := m.deps.PodLister.Pods(ns).Get(store.PodName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("storeUnderMaintenance: failed to get pod %s/%s, error: %s", ns, store.PodName, err)
+	}
+	_, ok := pod.Annotations[label.AnnTiKVEvictLeaderKey]
+	return ok, nil
+}
+
+// removeTombstoneStores removes the tombstone stores from PD once their Pods
+// have been deleted or have joined the cluster as new stores, so the store list
+// in PD does not accumulate dead entries after scaling in and failover.
+// PD can only remove all tombstone stores at once, including the TiFlash stores
+// and the stores of a heterogeneous cluster, so nothing is removed while any of
+// them is still needed by the scaler of its Pod.
+func (m *tikvMemberManager) removeTombstoneStores(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiKV.Phase != v1alpha1.NormalPhase || len(tc.Status.TiKV.TombstoneStores) == 0 {
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	tombstoneStoresInfo, err := pdCli.GetTombStoneStores()
+	if err != nil {
+		return err
+	}
+	for _, store := range tombstoneStoresInfo.Stores {
+		needed, err := m.tombstoneStoreNeeded(store)
+		if err != nil {
+			return err
+		}
+		if needed {
+			klog.V(4).Infof("TidbCluster: [%s/%s]'s tikv tombstone stores are kept because tombstone store %s is still needed", ns, tc.GetName(), store.Store.Address)
+			return nil
+		}
+	}
+
+	if err := pdCli.RemoveTombStone(); err != nil {
+		return err
+	}
+	klog.Infof("TidbCluster: [%s/%s]'s tikv tombstone stores are removed", ns, tc.GetName())
+	tc.Status.TiKV.TombstoneStores = nil
+	return nil
+}
+
+// tombstoneStoreNeeded returns whether the tombstone store is still needed by the scaler,
+// i.e. its Pod is not scaled in yet and still has the store ID label. The Pod is resolved
+// from the store address <pod>.<peer service>.<namespace>.svc, which is used by both TiKV
+// and TiFlash stores of all the clusters managed by TiDB Operator.
+func (m *tikvMemberManager) tombstoneStoreNeeded(store *pdapi.StoreInfo) (bool, error) {
+	if store.Store == nil || store.Store.Store == nil {
+		return false, nil
+	}
+	host := strings.Split(store.Store.Address, ":")[0]
+	parts := strings.Split(host, ".")
+	if len(parts) < 3 {
+		return false, nil
+	}
+	podName, ns := parts[0], parts[2]
+	pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("removeTombstoneStores: failed to get pod %s/%s, error: %s", ns, podName, err)
+	}
+	return pod.Labels[label.StoreIDLabelKey] == strconv.FormatUint(store.Store.Id, 10), nil
+}
+
 func (m *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (int, error) {
 	ns := tc.GetNamespace()
 	// for unit test
@@ -878,6 +1202,57 @@ func (m *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (int
 	return setCount, nil
 }
 
+// setStoreWeightsForTiKV sets the leader and region weight of the TiKV stores
+// in PD according to spec.tikv.storeWeight, the weights are left untouched if
+// it is not set.
+func (m *tikvMemberManager) setStoreWeightsForTiKV(tc *v1alpha1.TidbCluster) error {
+	weight := tc.Spec.TiKV.StoreWeight
+	if weight == nil || !tc.TiKVBootStrapped() {
+		return nil
+	}
+	leaderWeight, err := weight.GetLeaderWeight()
+	if err != nil {
+		return fmt.Errorf("setStoreWeightsForTiKV: invalid leader weight of tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	regionWeight, err := weight.GetRegionWeight()
+	if err != nil {
+		return fmt.Errorf("setStoreWeightsForTiKV: invalid region weight of tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return err
+	}
+
+	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tc.Name, tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Status == nil || !pattern.Match([]byte(store.Store.Address)) {
+			continue
+		}
+		if store.Store.StateName == v1alpha1.TiKVStateTombstone {
+			continue
+		}
+		if store.Status.LeaderWeight == leaderWeight && store.Status.RegionWeight == regionWeight {
+			continue
+		}
+		if err := pdCli.SetStoreWeight(store.Store.Id, leaderWeight, regionWeight); err != nil {
+			msg := fmt.Sprintf("failed to set leader weight %v and region weight %v for store (id: %d, address: %s): %v",
+				leaderWeight, regionWeight, store.Store.Id, store.Store.Address, err)
+			m.deps.Recorder.Event(tc, corev1.EventTypeWarning, FailedSetStoreWeight, msg)
+			errs = append(errs, fmt.Errorf("%s", msg))
+			continue
+		}
+		klog.Infof("TidbCluster: [%s/%s]'s tikv store %d set leader weight %v and region weight %v successfully",
+			tc.Namespace, tc.Name, store.Store.Id, leaderWeight, regionWeight)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
 func (m *tikvMemberManager) getNodeLabels(nodeName string, storeLabels []string) (map[string]string, error) {
 	node, err := m.deps.NodeLister.Get(nodeName)
 	if err != nil {
@@ -892,12 +1267,12 @@ func (m *tikvMemberManager) getNodeLabels(nodeName string, storeLabels []string)
 		}
 
 		// TODO after pd supports storeLabel containing slash character, these codes should be deleted
-		if storeLabel == "host" {
-			if host, found := ls[corev1.LabelHostname]; found {
-				labels[storeLabel] = host
+		for _, nodeLabel := range nodeTopologyLabels[storeLabel] {
+			if value, found := ls[nodeLabel]; found {
+				labels[storeLabel] = value
+				break
 			}
 		}
-
 	}
 	return labels, nil
 }
@@ -15,6 +15,7 @@ package member
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -815,6 +816,161 @@ func TestTiKVMemberManagerSetStoreLabelsForTiKV(t *testing.T) {
 	}
 }
 
+func TestTiKVMemberManagerSetStoreWeightsForTiKV(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		weight      *v1alpha1.StoreWeight
+		setErr      bool
+		errExpectFn func(*GomegaWithT, error)
+		expectSet   map[uint64][2]float64
+	}
+
+	newStore := func(id uint64, podName, state string, leaderWeight, regionWeight float64) *pdapi.StoreInfo {
+		return &pdapi.StoreInfo{
+			Store: &pdapi.MetaStore{
+				Store: &metapb.Store{
+					Id:      id,
+					Address: fmt.Sprintf("%s.test-tikv-peer.default.svc:20160", podName),
+				},
+				StateName: state,
+			},
+			Status: &pdapi.StoreStatus{
+				LeaderWeight: leaderWeight,
+				RegionWeight: regionWeight,
+			},
+		}
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Status.TiKV.BootStrapped = true
+		tc.Spec.TiKV.StoreWeight = test.weight
+		tkmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+		pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					newStore(1, "test-tikv-0", v1alpha1.TiKVStateUp, 1, 1),
+					newStore(2, "test-tikv-1", v1alpha1.TiKVStateUp, 2, 1),
+					newStore(3, "test-tikv-2", v1alpha1.TiKVStateTombstone, 1, 1),
+					newStore(4, "other-tikv-0", v1alpha1.TiKVStateUp, 1, 1),
+				},
+			}, nil
+		})
+		set := map[uint64][2]float64{}
+		pdClient.AddReaction(pdapi.SetStoreWeightActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.setErr {
+				return nil, fmt.Errorf("failed to set store weight")
+			}
+			set[action.ID] = [2]float64{action.LeaderWeight, action.RegionWeight}
+			return nil, nil
+		})
+
+		err := tkmm.setStoreWeightsForTiKV(tc)
+		test.errExpectFn(g, err)
+		g.Expect(set).To(Equal(test.expectSet))
+	}
+
+	tests := []testcase{
+		{
+			name:        "store weight is not set",
+			weight:      nil,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectSet:   map[uint64][2]float64{},
+		},
+		{
+			name:        "set leader weight",
+			weight:      &v1alpha1.StoreWeight{Leader: pointer.StringPtr("2")},
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectSet:   map[uint64][2]float64{1: {2, 1}},
+		},
+		{
+			name:        "reset to default weight",
+			weight:      &v1alpha1.StoreWeight{},
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectSet:   map[uint64][2]float64{2: {1, 1}},
+		},
+		{
+			name:        "failed to set store weight",
+			weight:      &v1alpha1.StoreWeight{Region: pointer.StringPtr("0.5")},
+			setErr:      true,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+			expectSet:   map[uint64][2]float64{},
+		},
+		{
+			name:        "invalid store weight",
+			weight:      &v1alpha1.StoreWeight{Leader: pointer.StringPtr("-1")},
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+			expectSet:   map[uint64][2]float64{},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerGetNodeLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name       string
+		nodeLabels map[string]string
+		expected   map[string]string
+	}{
+		{
+			name: "store labels on node",
+			nodeLabels: map[string]string{
+				"region":                        "region",
+				"zone":                          "zone",
+				"topology.kubernetes.io/zone":   "topology-zone",
+				corev1.LabelHostname:            "host",
+				"topology.kubernetes.io/region": "topology-region",
+			},
+			expected: map[string]string{
+				"region": "region",
+				"zone":   "zone",
+				"host":   "host",
+			},
+		},
+		{
+			name: "topology labels on node",
+			nodeLabels: map[string]string{
+				"topology.kubernetes.io/zone":   "topology-zone",
+				corev1.LabelZoneFailureDomain:   "beta-zone",
+				corev1.LabelZoneRegion:          "beta-region",
+				corev1.LabelHostname:            "host",
+				"failure-domain.example.com/dc": "dc",
+			},
+			expected: map[string]string{
+				"region": "beta-region",
+				"zone":   "topology-zone",
+				"host":   "host",
+			},
+		},
+		{
+			name:       "no labels on node",
+			nodeLabels: map[string]string{},
+			expected:   map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		tc := newTidbClusterForPD()
+		tkmm, _, _, _, _, nodeIndexer := newFakeTiKVMemberManager(tc)
+		nodeIndexer.Add(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-1",
+				Labels: tt.nodeLabels,
+			},
+		})
+		labels, err := tkmm.getNodeLabels("node-1", []string{"region", "zone", "rack", "host"})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(labels).To(Equal(tt.expected))
+	}
+}
+
 func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -1378,6 +1534,90 @@ func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
 			},
 		},
+		{
+			name: "store is still slow, SlowSince not change",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+					State:     v1alpha1.TiKVStateUp,
+					SlowScore: 100,
+					SlowSince: &now,
+				}
+			},
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TidbCluster) (bool, error) {
+				return false, nil
+			},
+			errWhenGetStores: false,
+			storeInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      333,
+								Address: fmt.Sprintf("%s-tikv-1.%s-tikv-peer.%s.svc:20160", "test", "test", "default"),
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+							SlowScore:       100,
+						},
+					},
+				},
+			},
+			errWhenGetTombstoneStores: false,
+			tombstoneStoreInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{},
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
+				g.Expect(tc.Status.TiKV.Stores["333"].SlowSince).To(Equal(&now))
+				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
+			},
+		},
+		{
+			name: "store recovers, SlowSince is unset",
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+					State:     v1alpha1.TiKVStateUp,
+					SlowScore: 100,
+					SlowSince: &now,
+				}
+			},
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TidbCluster) (bool, error) {
+				return false, nil
+			},
+			errWhenGetStores: false,
+			storeInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      333,
+								Address: fmt.Sprintf("%s-tikv-1.%s-tikv-peer.%s.svc:20160", "test", "test", "default"),
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+							SlowScore:       1,
+						},
+					},
+				},
+			},
+			errWhenGetTombstoneStores: false,
+			tombstoneStoreInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{},
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
+				g.Expect(tc.Status.TiKV.Stores["333"].SlowSince).To(BeNil())
+				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
+			},
+		},
 		{
 			name: "get tombstone stores failed",
 			updateTC: func(tc *v1alpha1.TidbCluster) {
@@ -1576,6 +1816,462 @@ func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 	}
 }
 
+func TestTiKVMemberManagerCleanupOrphanEvictLeaderSchedulers(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name          string
+		phase         v1alpha1.MemberPhase
+		maintaining   bool
+		endEvictErr   bool
+		errExpectFn   func(*GomegaWithT, error)
+		expectStoreID []uint64
+		expectOwned   bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Status.TiKV.Phase = test.phase
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0", EvictLeaderScheduler: "evict-leader-scheduler-1"},
+			"2": {ID: "2", PodName: "test-tikv-1"},
+			"3": {ID: "3", PodName: "test-tikv-2", EvictLeaderScheduler: "evict-leader-scheduler-3"},
+		}
+		// the scheduler of store 3 is not added by the operator, e.g. by pd-ctl
+		tc.Status.TiKV.EvictLeaderStores = map[string]metav1.Time{"1": metav1.Now()}
+
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		if test.maintaining {
+			podIndexer.Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-tikv-0",
+					Namespace:   tc.Namespace,
+					Annotations: map[string]string{label.AnnTiKVEvictLeaderKey: ""},
+				},
+			})
+		}
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+			return fmt.Sprintf("evict-leader-scheduler-%d", action.ID), nil
+		})
+		var endedStoreIDs []uint64
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.endEvictErr {
+				return nil, fmt.Errorf("failed to end evict leader")
+			}
+			endedStoreIDs = append(endedStoreIDs, action.ID)
+			return nil, nil
+		})
+
+		err := tkmm.cleanupOrphanEvictLeaderSchedulers(tc)
+		test.errExpectFn(g, err)
+		g.Expect(endedStoreIDs).To(Equal(test.expectStoreID))
+		if test.expectOwned {
+			g.Expect(tc.Status.TiKV.EvictLeaderStores).To(HaveKey("1"))
+		} else {
+			g.Expect(tc.Status.TiKV.EvictLeaderStores).NotTo(HaveKey("1"))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:          "remove orphaned scheduler in normal phase",
+			phase:         v1alpha1.NormalPhase,
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectStoreID: []uint64{1},
+		},
+		{
+			name:        "keep scheduler of pod under maintenance",
+			phase:       v1alpha1.NormalPhase,
+			maintaining: true,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectOwned: true,
+		},
+		{
+			name:        "keep scheduler in upgrade phase",
+			phase:       v1alpha1.UpgradePhase,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectOwned: true,
+		},
+		{
+			name:        "failed to end evict leader",
+			phase:       v1alpha1.NormalPhase,
+			endEvictErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+			expectOwned: true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerEvictLeadersOfSlowStores(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name                string
+		phase               v1alpha1.MemberPhase
+		slowStoreProtection bool
+		slowFor             time.Duration
+		evicting            bool
+		evictingOwned       bool
+		manualScheduler     bool
+		beginEvictErr       bool
+		errExpectFn         func(*GomegaWithT, error)
+		expectStoreID       []uint64
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.SlowStoreProtection = &test.slowStoreProtection
+		tc.Status.TiKV.Phase = test.phase
+		slowSince := metav1.NewTime(time.Now().Add(-test.slowFor))
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp, SlowScore: 100, SlowSince: &slowSince},
+			"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, SlowScore: 100, SlowSince: &slowSince},
+			"3": {ID: "3", PodName: "test-tikv-2", State: v1alpha1.TiKVStateUp, SlowScore: 1},
+		}
+		if test.evicting {
+			// the scheduler of store 3 is added by others, e.g. pd-ctl
+			store := tc.Status.TiKV.Stores["3"]
+			store.EvictLeaderScheduler = "evict-leader-scheduler-3"
+			tc.Status.TiKV.Stores["3"] = store
+		}
+		if test.evictingOwned {
+			// the scheduler of store 3 is added by the operator but not shown in the status yet
+			tc.Status.TiKV.EvictLeaderStores = map[string]metav1.Time{"3": metav1.Now()}
+		}
+
+		tkmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+		// the scheduler of store 1 is added by others but not shown in the status yet
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.manualScheduler && action.ID == 1 {
+				return "evict-leader-scheduler-1", nil
+			}
+			return "", nil
+		})
+		var evictedStoreIDs []uint64
+		pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.beginEvictErr {
+				return nil, fmt.Errorf("failed to begin evict leader")
+			}
+			evictedStoreIDs = append(evictedStoreIDs, action.ID)
+			return nil, nil
+		})
+
+		err := tkmm.evictLeadersOfSlowStores(tc)
+		test.errExpectFn(g, err)
+		g.Expect(evictedStoreIDs).To(Equal(test.expectStoreID))
+		for _, id := range test.expectStoreID {
+			g.Expect(tc.Status.TiKV.EvictLeaderStores).To(HaveKey(strconv.FormatUint(id, 10)))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:                "evict leaders of one slow store",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectStoreID:       []uint64{1},
+		},
+		{
+			name:                "stores are slow within the grace period",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             time.Minute,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "slow store protection is disabled",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: false,
+			slowFor:             10 * time.Minute,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "skip in upgrade phase",
+			phase:               v1alpha1.UpgradePhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "another store has an evict leader scheduler",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			evicting:            true,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "leaders of another store are evicted by the operator",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			evictingOwned:       true,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "slow store has an evict leader scheduler not shown in the status",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			manualScheduler:     true,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:                "failed to begin evict leader",
+			phase:               v1alpha1.NormalPhase,
+			slowStoreProtection: true,
+			slowFor:             10 * time.Minute,
+			beginEvictErr:       true,
+			errExpectFn:         func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerEvictLeadersForMaintenance(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name          string
+		phase         v1alpha1.MemberPhase
+		beginEvictErr bool
+		errExpectFn   func(*GomegaWithT, error)
+		expectStoreID []uint64
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Status.TiKV.Phase = test.phase
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp},
+			"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, EvictLeaderScheduler: "evict-leader-scheduler-2"},
+			"3": {ID: "3", PodName: "test-tikv-2", State: v1alpha1.TiKVStateUp},
+			"4": {ID: "4", PodName: "test-tikv-3", State: v1alpha1.TiKVStateUp},
+		}
+		// the scheduler of store 4 is added by the operator but not shown in the status yet
+		tc.Status.TiKV.EvictLeaderStores = map[string]metav1.Time{"4": metav1.Now()}
+
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		for _, podName := range []string{"test-tikv-0", "test-tikv-1", "test-tikv-2", "test-tikv-3"} {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: tc.Namespace,
+				},
+			}
+			if podName != "test-tikv-2" {
+				pod.Annotations = map[string]string{label.AnnTiKVEvictLeaderKey: ""}
+			}
+			podIndexer.Add(pod)
+		}
+		// the scheduler of store 2 is added by others, e.g. pd-ctl
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+			if action.ID == 2 {
+				return "evict-leader-scheduler-2", nil
+			}
+			return "", nil
+		})
+		var evictedStoreIDs []uint64
+		pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.beginEvictErr {
+				return nil, fmt.Errorf("failed to begin evict leader")
+			}
+			evictedStoreIDs = append(evictedStoreIDs, action.ID)
+			return nil, nil
+		})
+
+		err := tkmm.evictLeadersForMaintenance(tc)
+		test.errExpectFn(g, err)
+		g.Expect(evictedStoreIDs).To(Equal(test.expectStoreID))
+		for _, id := range test.expectStoreID {
+			g.Expect(tc.Status.TiKV.EvictLeaderStores).To(HaveKey(strconv.FormatUint(id, 10)))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:          "evict leaders of store under maintenance",
+			phase:         v1alpha1.NormalPhase,
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectStoreID: []uint64{1},
+		},
+		{
+			name:        "skip in upgrade phase",
+			phase:       v1alpha1.UpgradePhase,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:          "failed to begin evict leader",
+			phase:         v1alpha1.NormalPhase,
+			beginEvictErr: true,
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerRemoveTombstoneStores(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name          string
+		phase         v1alpha1.MemberPhase
+		podStoreID    string
+		pdTombstones  []string
+		neededPod     string
+		removeErr     bool
+		errExpectFn   func(*GomegaWithT, error)
+		expectRemoved bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Status.TiKV.Phase = test.phase
+		tc.Status.TiKV.TombstoneStores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-2", State: v1alpha1.TiKVStateTombstone},
+		}
+
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		if test.podStoreID != "" {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-tikv-2",
+					Namespace: tc.Namespace,
+					Labels:    map[string]string{label.StoreIDLabelKey: test.podStoreID},
+				},
+			}
+			podIndexer.Add(pod)
+		}
+		if test.neededPod != "" {
+			// the pod of the first tombstone store in pdTombstones is not scaled in yet
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      test.neededPod,
+					Namespace: tc.Namespace,
+					Labels:    map[string]string{label.StoreIDLabelKey: "100"},
+				},
+			}
+			podIndexer.Add(pod)
+		}
+		pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			storesInfo := &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      1,
+								Address: fmt.Sprintf("%s-tikv-2.%s-tikv-peer.%s.svc:20160", tc.Name, tc.Name, tc.Namespace),
+							},
+							StateName: v1alpha1.TiKVStateTombstone,
+						},
+					},
+				},
+			}
+			for i, addr := range test.pdTombstones {
+				storesInfo.Stores = append(storesInfo.Stores, &pdapi.StoreInfo{
+					Store: &pdapi.MetaStore{
+						Store:     &metapb.Store{Id: uint64(100 + i), Address: addr},
+						StateName: v1alpha1.TiKVStateTombstone,
+					},
+				})
+			}
+			return storesInfo, nil
+		})
+		removed := false
+		pdClient.AddReaction(pdapi.RemoveTombStoneActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.removeErr {
+				return nil, fmt.Errorf("failed to remove tombstone stores")
+			}
+			removed = true
+			return nil, nil
+		})
+
+		err := tkmm.removeTombstoneStores(tc)
+		test.errExpectFn(g, err)
+		g.Expect(removed).To(Equal(test.expectRemoved))
+		if test.expectRemoved {
+			g.Expect(tc.Status.TiKV.TombstoneStores).To(BeEmpty())
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:          "remove tombstone stores whose pods are deleted",
+			phase:         v1alpha1.NormalPhase,
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectRemoved: true,
+		},
+		{
+			name:          "remove tombstone stores whose pods have new stores",
+			phase:         v1alpha1.NormalPhase,
+			podStoreID:    "4",
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectRemoved: true,
+		},
+		{
+			name:        "keep tombstone stores whose pods are not scaled in",
+			phase:       v1alpha1.NormalPhase,
+			podStoreID:  "1",
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:          "remove tombstone stores when PD has tiflash tombstone stores",
+			phase:         v1alpha1.NormalPhase,
+			pdTombstones:  []string{"test-tiflash-0.test-tiflash-peer.default.svc:3930"},
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectRemoved: true,
+		},
+		{
+			name:          "remove tombstone stores when PD has tombstone stores of a heterogeneous cluster",
+			phase:         v1alpha1.NormalPhase,
+			pdTombstones:  []string{"hetero-tikv-0.hetero-tikv-peer.default.svc:20160", "external-tikv:20160"},
+			errExpectFn:   func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+			expectRemoved: true,
+		},
+		{
+			name:         "keep tombstone stores whose tiflash pods are not scaled in",
+			phase:        v1alpha1.NormalPhase,
+			pdTombstones: []string{"test-tiflash-0.test-tiflash-peer.default.svc:3930"},
+			neededPod:    "test-tiflash-0",
+			errExpectFn:  func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:         "keep tombstone stores whose heterogeneous cluster pods are not scaled in",
+			phase:        v1alpha1.NormalPhase,
+			pdTombstones: []string{"hetero-tikv-0.hetero-tikv-peer.default.svc:20160"},
+			neededPod:    "hetero-tikv-0",
+			errExpectFn:  func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:        "keep tombstone stores in scale phase",
+			phase:       v1alpha1.ScalePhase,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).NotTo(HaveOccurred()) },
+		},
+		{
+			name:        "failed to remove tombstone stores",
+			phase:       v1alpha1.NormalPhase,
+			removeErr:   true,
+			errExpectFn: func(g *GomegaWithT, err error) { g.Expect(err).To(HaveOccurred()) },
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
 func newFakeTiKVMemberManager(tc *v1alpha1.TidbCluster) (
 	*tikvMemberManager, *controller.FakeStatefulSetControl,
 	*controller.FakeServiceControl, *pdapi.FakePDClient, cache.Indexer, cache.Indexer) {
@@ -1933,6 +2629,157 @@ func TestGetNewTiKVSetForTidbCluster(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "tikv cpu pinning",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:   &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{
+						ResourceRequirements: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:     resource.MustParse("4"),
+								corev1.ResourceStorage: resource.MustParse("100Gi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+						CPUPinning: &v1alpha1.CPUPinning{
+							Enabled: true,
+						},
+					},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				resources := corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				}
+				g.Expect(sts.Spec.Template.Spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{
+					Requests: resources,
+					Limits:   resources,
+				}))
+			},
+		},
+		{
+			name: "tikv cpu pinning with init and additional containers",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:   &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{
+						ComponentSpec: v1alpha1.ComponentSpec{
+							InitContainers: []corev1.Container{{
+								Name: "init-sidecar",
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU:    resource.MustParse("100m"),
+										corev1.ResourceMemory: resource.MustParse("50Mi"),
+									},
+								},
+							}},
+							AdditionalContainers: []corev1.Container{{
+								Name: "sidecar",
+								Resources: corev1.ResourceRequirements{
+									Limits: corev1.ResourceList{
+										corev1.ResourceCPU:    resource.MustParse("100m"),
+										corev1.ResourceMemory: resource.MustParse("50Mi"),
+									},
+								},
+							}},
+						},
+						ResourceRequirements: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("4"),
+								corev1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+						CPUPinning: &v1alpha1.CPUPinning{
+							Enabled: true,
+						},
+					},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				resources := corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("50Mi"),
+				}
+				guaranteed := corev1.ResourceRequirements{
+					Requests: resources,
+					Limits:   resources,
+				}
+				podSpec := sts.Spec.Template.Spec
+				g.Expect(podSpec.InitContainers[len(podSpec.InitContainers)-1].Resources).To(Equal(guaranteed))
+				g.Expect(podSpec.Containers[len(podSpec.Containers)-1].Resources).To(Equal(guaranteed))
+			},
+		},
+		{
+			name: "tikv cpu pinning with non-guaranteed additional container",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:   &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{
+						ComponentSpec: v1alpha1.ComponentSpec{
+							AdditionalContainers: []corev1.Container{{Name: "sidecar"}},
+						},
+						ResourceRequirements: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("4"),
+								corev1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+						CPUPinning: &v1alpha1.CPUPinning{
+							Enabled: true,
+						},
+					},
+				},
+			},
+			wantErr: true,
+			testSts: func(sts *apps.StatefulSet) {},
+		},
+		{
+			name: "tikv cpu pinning with fractional cpu",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:   &v1alpha1.PDSpec{},
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{
+						ResourceRequirements: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("3500m"),
+								corev1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+						CPUPinning: &v1alpha1.CPUPinning{
+							Enabled: true,
+						},
+					},
+				},
+			},
+			wantErr: true,
+			testSts: func(sts *apps.StatefulSet) {},
+		},
 		// TODO add more tests
 	}
 
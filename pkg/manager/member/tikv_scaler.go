@@ -15,6 +15,8 @@ package member
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -42,6 +44,11 @@ func NewTiKVScaler(deps *controller.Dependencies) *tikvScaler {
 }
 
 func (s *tikvScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if tc, ok := meta.(*v1alpha1.TidbCluster); ok {
+		if err := s.applyScaleInPolicy(tc, oldSet, newSet); err != nil {
+			return err
+		}
+	}
 	scaling, _, _, _ := scaleOne(oldSet, newSet)
 	if scaling > 0 {
 		return s.ScaleOut(meta, oldSet, newSet)
@@ -129,7 +136,12 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 				}
 				klog.Infof("tikvScaler.ScaleIn: delete store %d for tikv %s/%s successfully", id, ns, podName)
 			}
-			return controller.RequeueErrorf("TiKV %s/%s store %d is still in cluster, state: %s", ns, podName, id, state)
+			regionCount, err := controller.GetPDClient(s.deps.PDControl, tc).GetRegionCountByStore(id)
+			if err != nil {
+				klog.Warningf("tikvScaler.ScaleIn: failed to get region count of store %d, %v", id, err)
+				return controller.RequeueErrorf("TiKV %s/%s store %d is still in cluster, state: %s", ns, podName, id, state)
+			}
+			return controller.RequeueErrorf("TiKV %s/%s store %d is still in cluster, state: %s, region count: %d", ns, podName, id, state, regionCount)
 		}
 	}
 
@@ -201,6 +213,110 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 	return fmt.Errorf("TiKV %s/%s not found in cluster", ns, podName)
 }
 
+// applyScaleInPolicy chooses the TiKV Pod to be removed on scale-in by spec.tikv.scaleInPolicy
+// and the topology spread constraints of TiKV, the ordinal of the Pod is added to the delete
+// slots of the desired statefulset so that scaleOne removes it, one Pod at a time, and recorded
+// in the delete slots annotation of the TidbCluster.
+func (s *tikvScaler) applyScaleInPolicy(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	policy := tc.Spec.TiKV.ScaleInPolicy
+	constraints := tc.BaseTiKVSpec().TopologySpreadConstraints()
+	if (policy == "" || policy == v1alpha1.ScaleInPolicyHighestOrdinal) && len(constraints) == 0 {
+		return nil
+	}
+
+	ordinals, choose := scaleInCandidates(oldSet, newSet)
+	if !choose {
+		return nil
+	}
+	ordinal, err := s.chooseScaleInOrdinal(tc, policy, constraints, ordinals)
+	if err != nil {
+		return err
+	}
+	klog.Infof("tikv scale-in policy %s of tc %s/%s chooses ordinal %d to be removed", policy, tc.Namespace, tc.Name, ordinal)
+	return setScaleInOrdinal(tc, label.AnnTiKVDeleteSlots, oldSet, newSet, ordinals, ordinal)
+}
+
+func (s *tikvScaler) chooseScaleInOrdinal(tc *v1alpha1.TidbCluster, policy v1alpha1.ScaleInPolicy, constraints []v1alpha1.TopologySpreadConstraint, ordinals []int32) (int32, error) {
+	ns := tc.GetNamespace()
+	stores := map[string]v1alpha1.TiKVStore{}
+	for _, store := range tc.Status.TiKV.Stores {
+		stores[store.PodName] = store
+	}
+	for _, ordinal := range ordinals {
+		if store, ok := stores[TikvPodName(tc.GetName(), ordinal)]; ok && store.State == v1alpha1.TiKVStateOffline {
+			// the store is going offline, continue to scale in the Pod
+			return ordinal, nil
+		}
+	}
+
+	highestOrdinal := ordinals[len(ordinals)-1]
+	ordinals, err := s.topologyEvenOrdinals(ns, constraints, ordinals, func(ordinal int32) string {
+		return TikvPodName(tc.GetName(), ordinal)
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	type candidate struct {
+		ordinal     int32
+		priority    int64
+		regionCount int32
+		// storeID is increasing in the order the stores are created, it's the max value
+		// if the Pod has no store yet
+		storeID uint64
+	}
+	var candidates []candidate
+	for _, ordinal := range ordinals {
+		podName := TikvPodName(tc.GetName(), ordinal)
+		pod, err := s.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				klog.Warningf("tikvScaler.chooseScaleInOrdinal: pod %s/%s is not found, remove the pod with the highest ordinal %d", ns, podName, highestOrdinal)
+				return highestOrdinal, nil
+			}
+			return -1, fmt.Errorf("tikvScaler.chooseScaleInOrdinal: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+		}
+		c := candidate{ordinal: ordinal, regionCount: stores[podName].RegionCount, storeID: math.MaxUint64}
+		if store, ok := stores[podName]; ok {
+			if id, err := strconv.ParseUint(store.ID, 10, 64); err == nil {
+				c.storeID = id
+			}
+		}
+		if v, ok := pod.Annotations[label.AnnScaleInPriority]; ok {
+			priority, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				klog.Warningf("tikvScaler.chooseScaleInOrdinal: invalid %s annotation %q of pod %s/%s, ignore it", label.AnnScaleInPriority, v, ns, podName)
+			} else {
+				c.priority = priority
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		switch policy {
+		case v1alpha1.ScaleInPolicyFewestRegions:
+			if a.regionCount != b.regionCount {
+				return a.regionCount < b.regionCount
+			}
+		case v1alpha1.ScaleInPolicyNewestPod:
+			if a.storeID != b.storeID {
+				return a.storeID > b.storeID
+			}
+		case v1alpha1.ScaleInPolicyOldestPod:
+			if a.storeID != b.storeID {
+				return a.storeID < b.storeID
+			}
+		}
+		return a.ordinal > b.ordinal
+	})
+	return candidates[0].ordinal, nil
+}
+
 // SyncAutoScalerAnn would reclaim the auto-scaling out slots if the target pod is no longer existed
 func (s *tikvScaler) SyncAutoScalerAnn(meta metav1.Object, actual *apps.StatefulSet) error {
 	tc, ok := meta.(*v1alpha1.TidbCluster)
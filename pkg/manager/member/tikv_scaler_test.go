@@ -19,14 +19,18 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
 )
@@ -630,3 +634,191 @@ func notReadyPodFunc(pod *corev1.Pod) {
 func errExpectRequeue(g *GomegaWithT, err error) {
 	g.Expect(controller.IsRequeueError(err)).To(Equal(true))
 }
+
+func TestTiKVScalerApplyScaleInPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name            string
+		policy          v1alpha1.ScaleInPolicy
+		newReplicas     int32
+		changeTc        func(*v1alpha1.TidbCluster)
+		oldDeleteSlots  sets.Int32
+		changePods      func([]*corev1.Pod)
+		expectReplicas  int32
+		expectDeleteSet sets.Int32
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.ScaleInPolicy = test.policy
+		normalStoreFun(tc)
+		if test.changeTc != nil {
+			test.changeTc(tc)
+		}
+
+		oldSet := newStatefulSetForPDScale()
+		oldSet.Name = fmt.Sprintf("%s-tikv", tc.Name)
+		if test.oldDeleteSlots != nil {
+			helper.SetDeleteSlots(oldSet, test.oldDeleteSlots)
+		}
+		// the desired delete slots come from the annotation of the TidbCluster only
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(test.newReplicas)
+		helper.SetDeleteSlots(newSet, sets.NewInt32())
+
+		scaler, _, _, podIndexer, _ := newFakeTiKVScaler()
+		var pods []*corev1.Pod
+		for i := int32(0); i < *oldSet.Spec.Replicas; i++ {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      TikvPodName(tc.GetName(), i),
+					Namespace: corev1.NamespaceDefault,
+				},
+			})
+		}
+		if test.changePods != nil {
+			test.changePods(pods)
+		}
+		for _, pod := range pods {
+			if pod != nil {
+				podIndexer.Add(pod)
+			}
+		}
+
+		err := scaler.applyScaleInPolicy(tc, oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*newSet.Spec.Replicas).To(Equal(test.expectReplicas))
+		g.Expect(helper.GetDeleteSlots(newSet)).To(Equal(test.expectDeleteSet))
+		if test.expectDeleteSet.Len() > 0 {
+			v, err := util.Encode(test.expectDeleteSet.List())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tc.Annotations[label.AnnTiKVDeleteSlots]).To(Equal(v))
+		} else {
+			g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnTiKVDeleteSlots))
+		}
+	}
+
+	setRegionCount := func(tc *v1alpha1.TidbCluster, ordinal int32, count int32) {
+		for id, store := range tc.Status.TiKV.Stores {
+			if store.PodName == TikvPodName(tc.GetName(), ordinal) {
+				store.RegionCount = count
+				tc.Status.TiKV.Stores[id] = store
+			}
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:            "highest ordinal",
+			policy:          v1alpha1.ScaleInPolicyHighestOrdinal,
+			newReplicas:     3,
+			expectReplicas:  3,
+			expectDeleteSet: sets.NewInt32(),
+		},
+		{
+			name:        "fewest regions",
+			policy:      v1alpha1.ScaleInPolicyFewestRegions,
+			newReplicas: 3,
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				for i := int32(0); i < 5; i++ {
+					setRegionCount(tc, i, 100)
+				}
+				setRegionCount(tc, 1, 10)
+			},
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(1),
+		},
+		{
+			name:            "newest pod has the largest store id",
+			policy:          v1alpha1.ScaleInPolicyNewestPod,
+			newReplicas:     3,
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(3),
+		},
+		{
+			name:        "newest pod has no store",
+			policy:      v1alpha1.ScaleInPolicyNewestPod,
+			newReplicas: 3,
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.TiKV.Stores, "11")
+			},
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(1),
+		},
+		{
+			name:            "oldest pod has the smallest store id",
+			policy:          v1alpha1.ScaleInPolicyOldestPod,
+			newReplicas:     3,
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(),
+		},
+		{
+			name:        "missing pod falls back to the highest ordinal",
+			policy:      v1alpha1.ScaleInPolicyFewestRegions,
+			newReplicas: 3,
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				setRegionCount(tc, 1, 10)
+			},
+			changePods: func(pods []*corev1.Pod) {
+				pods[2] = nil
+			},
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(),
+		},
+		{
+			name:           "delete slot removed from the annotation is not kept",
+			policy:         v1alpha1.ScaleInPolicyFewestRegions,
+			newReplicas:    5,
+			oldDeleteSlots: sets.NewInt32(1),
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				setRegionCount(tc, 2, 10)
+			},
+			expectReplicas:  5,
+			expectDeleteSet: sets.NewInt32(),
+		},
+		{
+			name:        "scale-in priority annotation",
+			policy:      v1alpha1.ScaleInPolicyOldestPod,
+			newReplicas: 4,
+			changePods: func(pods []*corev1.Pod) {
+				pods[2].Annotations = map[string]string{label.AnnScaleInPriority: "10"}
+				pods[3].Annotations = map[string]string{label.AnnScaleInPriority: "invalid"}
+			},
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(2),
+		},
+		{
+			name:        "offline store",
+			policy:      v1alpha1.ScaleInPolicyOldestPod,
+			newReplicas: 3,
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				for id, store := range tc.Status.TiKV.Stores {
+					if store.PodName == TikvPodName(tc.GetName(), 3) {
+						store.State = v1alpha1.TiKVStateOffline
+						tc.Status.TiKV.Stores[id] = store
+					}
+				}
+			},
+			expectReplicas:  4,
+			expectDeleteSet: sets.NewInt32(3),
+		},
+		{
+			name:        "not scaling in",
+			policy:      v1alpha1.ScaleInPolicyFewestRegions,
+			newReplicas: 5,
+			changeTc: func(tc *v1alpha1.TidbCluster) {
+				setRegionCount(tc, 1, 10)
+			},
+			expectReplicas:  5,
+			expectDeleteSet: sets.NewInt32(),
+		},
+	}
+
+	features.DefaultFeatureGate.Set("AdvancedStatefulSet=true")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFn(tt, t)
+		})
+	}
+}
@@ -16,14 +16,17 @@ package member
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
@@ -32,6 +35,8 @@ import (
 const (
 	// EvictLeaderBeginTime is the key of evict Leader begin time
 	EvictLeaderBeginTime = "evictLeaderBeginTime"
+	// tikvUpgradeRequeueInterval is the interval to check again while waiting for a TiKV Pod during the upgrade
+	tikvUpgradeRequeueInterval = 10 * time.Second
 )
 
 type TiKVUpgrader interface {
@@ -83,7 +88,12 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 	}
 
 	if status.StatefulSet.UpdateRevision == status.StatefulSet.CurrentRevision {
-		return nil
+		// All Pods are upgraded, run the post-upgrade hook unless the status of
+		// the StatefulSet is out of date.
+		if oldSet.Status.ObservedGeneration < oldSet.Generation {
+			return nil
+		}
+		return runUpgradeHook(u.deps, tc, v1alpha1.TiKVMemberType, postUpgradeHook, tc.TiKVPostUpgradeHook(), status.StatefulSet.UpdateRevision, &status.PostUpgradeHookRevision)
 	}
 
 	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
@@ -97,9 +107,32 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 	}
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	if upgradePaused(tc, label.AnnTiKVPauseUpgrade) {
+		klog.Infof("tidbcluster: [%s/%s]'s tikv upgrade is paused by annotation %s", ns, tcName, label.AnnTiKVPauseUpgrade)
+		// The Pod upgraded right before the pause still has its leaders evicted,
+		// let it serve leaders again while the upgrade is paused.
+		return u.endEvictLeaderOfUpgradedPod(tc, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	}
+	if err := runUpgradeHook(u.deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, tc.TiKVPreUpgradeHook(), status.StatefulSet.UpdateRevision, &status.PreUpgradeHookRevision); err != nil {
+		return err
+	}
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	partition := tc.TiKVUpdatePartition()
+	canaryPartition := u.tikvCanaryPartition(tc, podOrdinals, status.StatefulSet.UpdateRevision)
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < partition {
+			klog.Infof("tidbcluster: [%s/%s]'s tikv pods with ordinal less than update partition %d are not upgraded", ns, tcName, partition)
+			return nil
+		}
+		if i < canaryPartition {
+			if status.CanaryRevision != status.StatefulSet.UpdateRevision {
+				status.CanaryRevision = status.StatefulSet.UpdateRevision
+				status.CanaryUpdatedAt = metav1.Now()
+			}
+			klog.Infof("tidbcluster: [%s/%s]'s canary tikv pods are upgraded to revision %s, waiting for promotion", ns, tcName, status.CanaryRevision)
+			return nil
+		}
 		store := getStoreByOrdinal(meta.GetName(), *status, i)
 		if store == nil {
 			setUpgradePartition(newSet, i)
@@ -118,10 +151,10 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		if revision == status.StatefulSet.UpdateRevision {
 
 			if !podutil.IsPodReady(pod) {
-				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not ready", ns, tcName, podName)
+				return controller.RequeueAfterErrorf(tikvUpgradeRequeueInterval, "WaitingForPodReady", "tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not ready", ns, tcName, podName)
 			}
 			if store.State != v1alpha1.TiKVStateUp {
-				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
+				return controller.RequeueAfterErrorf(tikvUpgradeRequeueInterval, "WaitingForStoreUp", "tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
 			}
 
 			if !u.deps.CLIConfig.PodWebhookEnabled {
@@ -138,7 +171,14 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 			continue
 		}
 
+		if err := checkDisruptionAllowed(u.deps, tc, controller.TiKVMemberName(tcName)); err != nil {
+			return err
+		}
+
 		if u.deps.CLIConfig.PodWebhookEnabled {
+			if err := checkTiKVDiskUsage(tc, podName); err != nil {
+				return err
+			}
 			setUpgradePartition(newSet, i)
 			return nil
 		}
@@ -149,6 +189,54 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 	return nil
 }
 
+// tikvCanaryPartition returns the partition which leaves only the canary TiKV Pods to be upgraded
+// to the update revision, it's 0 if canary is not set or the canary rollout has been promoted.
+// The rollout is promoted automatically only if the canary Pods are healthy in this sync, otherwise
+// the countdown of autoPromoteAfter restarts.
+func (u *tikvUpgrader) tikvCanaryPartition(tc *v1alpha1.TidbCluster, podOrdinals []int32, updateRevision string) int32 {
+	canary := tc.Spec.TiKV.Canary
+	if canary == nil || canary.Replicas <= 0 || int(canary.Replicas) >= len(podOrdinals) {
+		return 0
+	}
+	if tc.Annotations[label.AnnTiKVCanaryPromote] == updateRevision {
+		return 0
+	}
+	canaryOrdinals := podOrdinals[len(podOrdinals)-int(canary.Replicas):]
+	if canary.AutoPromoteAfter != nil && tc.Status.TiKV.CanaryRevision == updateRevision {
+		autoPromoteAfter, err := time.ParseDuration(*canary.AutoPromoteAfter)
+		if err != nil {
+			klog.Errorf("tidbcluster: [%s/%s] invalid canary autoPromoteAfter %q, error: %v", tc.Namespace, tc.Name, *canary.AutoPromoteAfter, err)
+		} else if !u.tikvCanaryPodsHealthy(tc, canaryOrdinals, updateRevision) {
+			klog.Infof("tidbcluster: [%s/%s]'s canary tikv pods are not healthy, restart the countdown of auto promotion", tc.Namespace, tc.Name)
+			tc.Status.TiKV.CanaryUpdatedAt = metav1.Now()
+		} else if time.Since(tc.Status.TiKV.CanaryUpdatedAt.Time) >= autoPromoteAfter {
+			return 0
+		}
+	}
+	return canaryOrdinals[0]
+}
+
+// tikvCanaryPodsHealthy returns whether the canary TiKV Pods are upgraded to the update revision,
+// ready and their stores are Up.
+func (u *tikvUpgrader) tikvCanaryPodsHealthy(tc *v1alpha1.TidbCluster, ordinals []int32, updateRevision string) bool {
+	for _, ordinal := range ordinals {
+		podName := TikvPodName(tc.GetName(), ordinal)
+		pod, err := u.deps.PodLister.Pods(tc.GetNamespace()).Get(podName)
+		if err != nil {
+			klog.Warningf("tidbcluster: [%s/%s] failed to get canary tikv pod %s, error: %v", tc.Namespace, tc.Name, podName, err)
+			return false
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != updateRevision || !podutil.IsPodReady(pod) {
+			return false
+		}
+		store := getStoreByOrdinal(tc.GetName(), tc.Status.TiKV, ordinal)
+		if store == nil || store.State != v1alpha1.TiKVStateUp {
+			return false
+		}
+	}
+	return true
+}
+
 func (u *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -166,6 +254,9 @@ func (u *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, n
 			}
 			_, evicting := upgradePod.Annotations[EvictLeaderBeginTime]
 			if !evicting {
+				if err := checkTiKVDiskUsage(tc, upgradePodName); err != nil {
+					return err
+				}
 				return u.beginEvictLeader(tc, storeID, upgradePod)
 			}
 
@@ -174,13 +265,29 @@ func (u *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, n
 				return nil
 			}
 
-			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv pod: [%s] is evicting leader", ns, tcName, upgradePodName)
+			return controller.RequeueAfterErrorf(tikvUpgradeRequeueInterval, "WaitingForLeaderEviction", "tidbcluster: [%s/%s]'s tikv pod: [%s] is evicting leader", ns, tcName, upgradePodName)
 		}
 	}
 
 	return controller.RequeueErrorf("tidbcluster: [%s/%s] no store status found for tikv pod: [%s]", ns, tcName, upgradePodName)
 }
 
+// checkTiKVDiskUsage blocks the upgrade of the TiKV Pod if the disk usage of any
+// store exceeds the threshold, the regions rebalanced during the restart may fill
+// up the disks in that case.
+func checkTiKVDiskUsage(tc *v1alpha1.TidbCluster, podName string) error {
+	stores := tc.TiKVHighDiskUsageStores()
+	if len(stores) == 0 {
+		return nil
+	}
+	usages := make([]string, 0, len(stores))
+	for _, store := range stores {
+		usages = append(usages, fmt.Sprintf("%s(%d%%)", store.PodName, store.DiskUsagePercent))
+	}
+	return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv pod: [%s] can not be upgraded, disk usage of stores %s exceeds %d%%",
+		tc.Namespace, tc.Name, podName, strings.Join(usages, ","), *tc.Spec.TiKV.UpgradeDiskUsageThreshold)
+}
+
 func (u *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, tc *v1alpha1.TidbCluster) bool {
 	evictLeaderTimeout := tc.TiKVEvictLeaderTimeout()
 	tlsEnabled := tc.IsTLSClusterEnabled()
@@ -211,10 +318,38 @@ func (u *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, tc *v1alpha1.TidbC
 	return false
 }
 
+// endEvictLeaderOfUpgradedPod removes the evict leader scheduler of the store on the Pod
+// with the ordinal if the Pod has been upgraded and the store is Up again.
+func (u *tikvUpgrader) endEvictLeaderOfUpgradedPod(tc *v1alpha1.TidbCluster, ordinal int32) error {
+	if u.deps.CLIConfig.PodWebhookEnabled {
+		return nil
+	}
+	store := getStoreByOrdinal(tc.GetName(), tc.Status.TiKV, ordinal)
+	if store == nil || store.State != v1alpha1.TiKVStateUp {
+		return nil
+	}
+	podName := TikvPodName(tc.GetName(), ordinal)
+	pod, err := u.deps.PodLister.Pods(tc.GetNamespace()).Get(podName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("tikvUpgrader.endEvictLeaderOfUpgradedPod: failed to get pod %s for cluster %s/%s, error: %s", podName, tc.GetNamespace(), tc.GetName(), err)
+	}
+	if pod.Labels[apps.ControllerRevisionHashLabelKey] != tc.Status.TiKV.StatefulSet.UpdateRevision || !podutil.IsPodReady(pod) {
+		return nil
+	}
+	storeID, err := strconv.ParseUint(store.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	return endEvictLeaderbyStoreID(u.deps, tc, storeID)
+}
+
 func (u *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TidbCluster, storeID uint64, pod *corev1.Pod) error {
 	ns := tc.GetNamespace()
 	podName := pod.GetName()
-	err := controller.GetPDClient(u.deps.PDControl, tc).BeginEvictLeader(storeID)
+	err := beginEvictLeaderbyStoreID(u.deps, tc, storeID)
 	if err != nil {
 		klog.Errorf("tikv upgrader: failed to begin evict leader: %d, %s/%s, %v",
 			storeID, ns, podName, err)
@@ -251,23 +386,51 @@ func endEvictLeader(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ord
 	return endEvictLeaderbyStoreID(deps, tc, storeID)
 }
 
+// beginEvictLeaderbyStoreID adds the evict leader scheduler of the store and records the store
+// in the status, so that the scheduler is known to be added by TiDB Operator.
+func beginEvictLeaderbyStoreID(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, storeID uint64) error {
+	if err := controller.GetPDClient(deps.PDControl, tc).BeginEvictLeader(storeID); err != nil {
+		return err
+	}
+	id := strconv.FormatUint(storeID, 10)
+	if tc.Status.TiKV.EvictLeaderStores == nil {
+		tc.Status.TiKV.EvictLeaderStores = map[string]metav1.Time{}
+	}
+	if _, exist := tc.Status.TiKV.EvictLeaderStores[id]; !exist {
+		tc.Status.TiKV.EvictLeaderStores[id] = metav1.Now()
+	}
+	return nil
+}
+
 func endEvictLeaderbyStoreID(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, storeID uint64) error {
 	// wait 5 second before delete evict scheduler，it is for auto test can catch these info
 	if deps.CLIConfig.TestMode {
 		time.Sleep(5 * time.Second)
 	}
-	var err error
-
+	var pdClient pdapi.PDClient
 	if tc.IsHeterogeneous() {
-		err = deps.PDControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled()).EndEvictLeader(storeID)
+		pdClient = deps.PDControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled())
 	} else {
-		err = deps.PDControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled()).EndEvictLeader(storeID)
+		pdClient = deps.PDControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled())
+	}
+
+	scheduler, err := pdClient.GetEvictLeaderScheduler(storeID)
+	if err != nil {
+		klog.Errorf("tikv: failed to get evict leader scheduler for store: %d of %s/%s, error: %v", storeID, tc.Namespace, tc.Name, err)
+		return err
+	}
+	if scheduler == "" {
+		klog.V(4).Infof("tikv: no evict leader scheduler exists for store: %d of %s/%s", storeID, tc.Namespace, tc.Name)
+		delete(tc.Status.TiKV.EvictLeaderStores, strconv.FormatUint(storeID, 10))
+		return nil
 	}
 
+	err = pdClient.EndEvictLeader(storeID)
 	if err != nil {
 		klog.Errorf("tikv: failed to end evict leader for store: %d of %s/%s, error: %v", storeID, tc.Namespace, tc.Name, err)
 		return err
 	}
+	delete(tc.Status.TiKV.EvictLeaderStores, strconv.FormatUint(storeID, 10))
 	klog.Infof("tikv: end evict leader for store: %d of %s/%s successfully", storeID, tc.Namespace, tc.Name)
 	return nil
 }
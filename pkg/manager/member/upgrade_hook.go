@@ -0,0 +1,143 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	preUpgradeHook  = "pre-upgrade"
+	postUpgradeHook = "post-upgrade"
+
+	// upgradeHookJobTTLSeconds is the time to live of the finished hook Jobs,
+	// the completion of the hooks is recorded in the status of TidbCluster.
+	upgradeHookJobTTLSeconds = 24 * 60 * 60
+)
+
+// upgradeHookJobName returns the name of the hook Job of the update revision,
+// the revision name already contains the StatefulSet name. The name is used as
+// the job-name label of the hook Pods, so it is truncated and suffixed with the
+// hash of the full name if it is longer than 63 characters.
+func upgradeHookJobName(updateRevision, hook string) string {
+	name := fmt.Sprintf("%s-%s", updateRevision, hook)
+	if len(name) <= validation.DNS1123LabelMaxLength {
+		return name
+	}
+	hash := v1alpha1.HashContents([]byte(name))
+	return fmt.Sprintf("%s-%s", name[:validation.DNS1123LabelMaxLength-len(hash)-1], hash)
+}
+
+// getUpgradeHookJob returns the Job which runs the hook container once for the update revision.
+func getUpgradeHookJob(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, hook string, container *corev1.Container, updateRevision string) *batchv1.Job {
+	jobLabel := label.New().Instance(tc.GetInstanceName()).UpgradeHookJob()
+
+	c := container.DeepCopy()
+	c.Env = append(c.Env,
+		corev1.EnvVar{
+			Name:  "CLUSTER_NAME",
+			Value: tc.GetName(),
+		},
+		corev1.EnvVar{
+			Name:  "COMPONENT",
+			Value: memberType.String(),
+		},
+		corev1.EnvVar{
+			Name:  "UPDATE_REVISION",
+			Value: updateRevision,
+		},
+	)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            upgradeHookJobName(updateRevision, hook),
+			Namespace:       tc.GetNamespace(),
+			Labels:          jobLabel.Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: pointer.Int32Ptr(upgradeHookJobTTLSeconds),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabel.Labels(),
+				},
+				Spec: corev1.PodSpec{
+					Containers:       []corev1.Container{*c},
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: tc.Spec.ImagePullSecrets,
+				},
+			},
+		},
+	}
+}
+
+// runUpgradeHook creates the hook Job of the update revision if it does not exist, and returns
+// a requeue error until the Job completes, so that the caller does not go on before that.
+// The update revision is recorded in completedRevision when the Job completes, so that the
+// hook is not run again after the Job is deleted. It returns nil if the hook is not configured.
+func runUpgradeHook(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, hook string, container *corev1.Container, updateRevision string, completedRevision *string) error {
+	if upgradeHookCompleted(container, *completedRevision, updateRevision) {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	jobName := upgradeHookJobName(updateRevision, hook)
+
+	job, err := deps.JobLister.Jobs(ns).Get(jobName)
+	if errors.IsNotFound(err) {
+		job = getUpgradeHookJob(tc, memberType, hook, container, updateRevision)
+		if err := deps.JobControl.CreateJob(tc, job); err != nil {
+			return err
+		}
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s %s hook job %s is created, waiting for it to complete", ns, tcName, memberType, hook, jobName)
+	}
+	if err != nil {
+		return fmt.Errorf("runUpgradeHook: failed to get job %s for cluster %s/%s, error: %s", jobName, ns, tcName, err)
+	}
+
+	switch {
+	case upgradeHookJobConditionTrue(job, batchv1.JobComplete):
+		*completedRevision = updateRevision
+		klog.Infof("tidbcluster: [%s/%s]'s %s %s hook job %s completed", ns, tcName, memberType, hook, jobName)
+		return nil
+	case upgradeHookJobConditionTrue(job, batchv1.JobFailed):
+		return fmt.Errorf("tidbcluster: [%s/%s]'s %s %s hook job %s failed, delete it to retry", ns, tcName, memberType, hook, jobName)
+	}
+	return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s %s hook job %s is not completed yet", ns, tcName, memberType, hook, jobName)
+}
+
+// upgradeHookCompleted returns whether the hook of the update revision has completed, or the hook is not configured.
+func upgradeHookCompleted(container *corev1.Container, completedRevision, updateRevision string) bool {
+	return container == nil || completedRevision == updateRevision
+}
+
+func upgradeHookJobConditionTrue(job *batchv1.Job, condType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == condType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
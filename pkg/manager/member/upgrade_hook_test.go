@@ -0,0 +1,112 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestGetUpgradeHookJob(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	container := &corev1.Container{Name: "hook", Image: "busybox"}
+	job := getUpgradeHookJob(tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, "test-tikv-abc")
+
+	g.Expect(job.Name).To(Equal("test-tikv-abc-pre-upgrade"))
+	g.Expect(job.Namespace).To(Equal(tc.Namespace))
+	g.Expect(job.Labels[label.ComponentLabelKey]).To(Equal(label.UpgradeHookJobLabelVal))
+	g.Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	g.Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "UPDATE_REVISION", Value: "test-tikv-abc"}))
+	g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "COMPONENT", Value: "tikv"}))
+	// the container in spec is not modified
+	g.Expect(container.Env).To(BeEmpty())
+}
+
+func TestUpgradeHookJobName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(upgradeHookJobName("test-tikv-abc", postUpgradeHook)).To(Equal("test-tikv-abc-post-upgrade"))
+
+	revision := strings.Repeat("a", 50) + "-tikv-abc"
+	name := upgradeHookJobName(revision, postUpgradeHook)
+	g.Expect(name).To(HaveLen(63))
+	g.Expect(name).To(HavePrefix(revision))
+	// the names of the hooks are still different after truncated
+	g.Expect(upgradeHookJobName(revision, preUpgradeHook)).NotTo(Equal(name))
+}
+
+func TestRunUpgradeHook(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	deps := controller.NewFakeDependencies()
+	indexer := deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer()
+	container := &corev1.Container{Name: "hook", Image: "busybox"}
+	revision := "test-tikv-abc"
+	completedRevision := ""
+
+	// not configured
+	g.Expect(runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, nil, revision, &completedRevision)).To(Succeed())
+	g.Expect(upgradeHookCompleted(nil, completedRevision, revision)).To(BeTrue())
+	g.Expect(completedRevision).To(BeEmpty())
+
+	// the job is created
+	err := runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, revision, &completedRevision)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	job, err := deps.JobLister.Jobs(tc.Namespace).Get(upgradeHookJobName(revision, preUpgradeHook))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(job.Spec.TTLSecondsAfterFinished).NotTo(BeNil())
+	g.Expect(upgradeHookCompleted(container, completedRevision, revision)).To(BeFalse())
+
+	// the job is still running
+	err = runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, revision, &completedRevision)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+
+	// the job failed
+	failed := job.DeepCopy()
+	failed.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	g.Expect(indexer.Update(failed)).To(Succeed())
+	err = runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, revision, &completedRevision)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(controller.IsRequeueError(err)).To(BeFalse())
+	g.Expect(completedRevision).To(BeEmpty())
+
+	// the job completed
+	complete := job.DeepCopy()
+	complete.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	g.Expect(indexer.Update(complete)).To(Succeed())
+	g.Expect(runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, revision, &completedRevision)).To(Succeed())
+	g.Expect(completedRevision).To(Equal(revision))
+	g.Expect(upgradeHookCompleted(container, completedRevision, revision)).To(BeTrue())
+
+	// the job is not created again after it is deleted
+	g.Expect(indexer.Delete(complete)).To(Succeed())
+	g.Expect(runUpgradeHook(deps, tc, v1alpha1.TiKVMemberType, preUpgradeHook, container, revision, &completedRevision)).To(Succeed())
+	_, err = deps.JobLister.Jobs(tc.Namespace).Get(upgradeHookJobName(revision, preUpgradeHook))
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	// the hook of a new revision is not completed
+	g.Expect(upgradeHookCompleted(container, completedRevision, "test-tikv-def")).To(BeFalse())
+}
@@ -15,6 +15,7 @@ package member
 
 import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 )
 
@@ -28,3 +29,10 @@ type Upgrader interface {
 type DMUpgrader interface {
 	Upgrade(*v1alpha1.DMCluster, *apps.StatefulSet, *apps.StatefulSet) error
 }
+
+// upgradePaused returns whether the rolling update of a component is paused by the
+// annotation of the tidbcluster, the pods already updated are kept and the others
+// are not updated until the annotation is removed.
+func upgradePaused(tc *v1alpha1.TidbCluster, annKey string) bool {
+	return tc.GetAnnotations()[annKey] == label.AnnPauseUpgradeVal
+}
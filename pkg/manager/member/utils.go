@@ -33,7 +33,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
@@ -151,6 +153,14 @@ func tidbPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiDBMemberName(tcName), ordinal)
 }
 
+func ticdcPodName(tcName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", controller.TiCDCMemberName(tcName), ordinal)
+}
+
+func pumpPodName(tcName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", controller.PumpMemberName(tcName), ordinal)
+}
+
 func DMMasterPodName(dcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.DMMasterMemberName(dcName), ordinal)
 }
@@ -173,6 +183,92 @@ func CombineAnnotations(a, b map[string]string) map[string]string {
 	return a
 }
 
+// suspendStatefulSet scales the StatefulSet of a suspended component to 0 directly, the scaler is
+// bypassed so that the members are not removed from the cluster
+func suspendStatefulSet(setCtl controller.StatefulSetControlInterface, tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	if set == nil || (set.Spec.Replicas != nil && *set.Spec.Replicas == 0) {
+		return nil
+	}
+	newSet := set.DeepCopy()
+	replicas := int32(0)
+	newSet.Spec.Replicas = &replicas
+	klog.Infof("suspend statefulset %s/%s by scaling it to 0", set.GetNamespace(), set.GetName())
+	_, err := setCtl.UpdateStatefulSet(tc, newSet)
+	return err
+}
+
+// CombineLabels merges the custom labels of the component with the labels managed by TiDB Operator,
+// the managed labels take precedence so that the Pods are always selected by the StatefulSet
+func CombineLabels(custom, managed map[string]string) map[string]string {
+	l := make(map[string]string, len(custom)+len(managed))
+	for k, v := range custom {
+		l[k] = v
+	}
+	for k, v := range managed {
+		l[k] = v
+	}
+	return l
+}
+
+// buildReadinessProbe builds the readiness probe of the component container with the given handler,
+// the defaults are overridden by the readinessProbe of the component if specified
+func buildReadinessProbe(probe *v1alpha1.Probe, handler corev1.Handler) *corev1.Probe {
+	p := &corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: int32(10),
+	}
+	if probe == nil {
+		return p
+	}
+	if probe.InitialDelaySeconds != nil {
+		p.InitialDelaySeconds = *probe.InitialDelaySeconds
+	}
+	if probe.PeriodSeconds != nil {
+		p.PeriodSeconds = *probe.PeriodSeconds
+	}
+	if probe.TimeoutSeconds != nil {
+		p.TimeoutSeconds = *probe.TimeoutSeconds
+	}
+	if probe.SuccessThreshold != nil {
+		p.SuccessThreshold = *probe.SuccessThreshold
+	}
+	if probe.FailureThreshold != nil {
+		p.FailureThreshold = *probe.FailureThreshold
+	}
+	return p
+}
+
+// buildTCPReadinessProbe builds the readiness probe which connects the given port of the component container,
+// it returns nil if the readinessProbe of the component is not specified to avoid rolling update the existing Pods
+func buildTCPReadinessProbe(probe *v1alpha1.Probe, port int) *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+	return buildReadinessProbe(probe, corev1.Handler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(port),
+		},
+	})
+}
+
+// getTopologySpreadConstraints converts the topology spread constraints of a component
+// to the ones of its Pods, which spread the Pods with the given labels evenly.
+func getTopologySpreadConstraints(constraints []v1alpha1.TopologySpreadConstraint, l label.Label) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+	tscs := make([]corev1.TopologySpreadConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		tscs = append(tscs, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       c.TopologyKey,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     l.Copy().LabelSelector(),
+		})
+	}
+	return tscs
+}
+
 // NeedForceUpgrade check if force upgrade is necessary
 func NeedForceUpgrade(ann map[string]string) bool {
 	// Check if annotation 'pingcap.com/force-upgrade: "true"' is set
@@ -185,6 +281,45 @@ func NeedForceUpgrade(ann map[string]string) bool {
 	return false
 }
 
+// guaranteedResources returns the resources with the request and the limit of cpu and memory
+// defaulting to each other, so that the container is in the Guaranteed QoS class
+func guaranteedResources(req corev1.ResourceRequirements) corev1.ResourceRequirements {
+	res := req.DeepCopy()
+	if res.Requests == nil {
+		res.Requests = corev1.ResourceList{}
+	}
+	if res.Limits == nil {
+		res.Limits = corev1.ResourceList{}
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if request, ok := res.Requests[name]; ok {
+			if _, ok := res.Limits[name]; !ok {
+				res.Limits[name] = request.DeepCopy()
+			}
+		} else if limit, ok := res.Limits[name]; ok {
+			res.Requests[name] = limit.DeepCopy()
+		}
+	}
+	return *res
+}
+
+// setGuaranteedResources sets the resources of the containers to be in the Guaranteed QoS class,
+// see guaranteedResources
+func setGuaranteedResources(containers []corev1.Container) {
+	for i := range containers {
+		containers[i].Resources = guaranteedResources(containers[i].Resources)
+	}
+}
+
+// numactlArgs returns the arguments of numactl to bind the processes to the NUMA node of the
+// CPU pinning, it's empty if the processes are not bound to any NUMA node
+func numactlArgs(pinning *v1alpha1.CPUPinning) string {
+	if pinning == nil || !pinning.Enabled || pinning.NUMANode == nil {
+		return ""
+	}
+	return fmt.Sprintf("--cpunodebind=%d --membind=%d", *pinning.NUMANode, *pinning.NUMANode)
+}
+
 // FindConfigMapVolume returns the configmap which's name matches the predicate in a PodSpec, empty indicates not found
 func FindConfigMapVolume(podSpec *corev1.PodSpec, pred func(string) bool) string {
 	for _, vol := range podSpec.Volumes {
@@ -262,6 +397,39 @@ func MapContainers(podSpec *corev1.PodSpec) map[string]corev1.Container {
 	return m
 }
 
+// applyPodSpecOverlay merges the overlay into the generated Pod spec as a strategic merge patch,
+// so the lists like containers, volumes and env are merged by their keys instead of being replaced.
+func applyPodSpecOverlay(podSpec corev1.PodSpec, overlay *runtime.RawExtension) (corev1.PodSpec, error) {
+	if overlay == nil {
+		return podSpec, nil
+	}
+	patch := overlay.Raw
+	if len(patch) == 0 && overlay.Object != nil {
+		b, err := json.Marshal(overlay.Object)
+		if err != nil {
+			return podSpec, fmt.Errorf("failed to marshal pod spec overlay, error: %v", err)
+		}
+		patch = b
+	}
+	if len(patch) == 0 {
+		return podSpec, nil
+	}
+
+	original, err := json.Marshal(podSpec)
+	if err != nil {
+		return podSpec, fmt.Errorf("failed to marshal pod spec, error: %v", err)
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodSpec{})
+	if err != nil {
+		return podSpec, fmt.Errorf("failed to apply pod spec overlay, error: %v", err)
+	}
+	result := corev1.PodSpec{}
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return podSpec, fmt.Errorf("failed to unmarshal pod spec with overlay, error: %v", err)
+	}
+	return result, nil
+}
+
 // UpdateStatefulSet is a template function to update the statefulset of components
 func UpdateStatefulSet(setCtl controller.StatefulSetControlInterface, object runtime.Object, newSet, oldSet *apps.StatefulSet) error {
 	isOrphan := metav1.GetControllerOf(oldSet) == nil
@@ -23,13 +23,17 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 )
 
 func TestStatefulSetIsUpgrading(t *testing.T) {
@@ -798,6 +802,158 @@ func TestCombineAnnotations(t *testing.T) {
 	}
 }
 
+func TestSuspendStatefulSet(t *testing.T) {
+	g := NewGomegaWithT(t)
+	setInformer := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Apps().V1().StatefulSets()
+	setCtl := controller.NewFakeStatefulSetControl(setInformer)
+	tc := &v1alpha1.TidbCluster{}
+
+	// the statefulset is not created yet
+	g.Expect(suspendStatefulSet(setCtl, tc, nil)).To(Succeed())
+
+	set := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tiflash",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+		},
+	}
+	g.Expect(setInformer.Informer().GetIndexer().Add(set)).To(Succeed())
+	g.Expect(suspendStatefulSet(setCtl, tc, set)).To(Succeed())
+	got, err := setInformer.Lister().StatefulSets(metav1.NamespaceDefault).Get("test-tiflash")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*got.Spec.Replicas).To(Equal(int32(0)))
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(3)))
+}
+
+func TestCombineLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		custom   map[string]string
+		managed  map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "custom is nil",
+			custom:   nil,
+			managed:  map[string]string{label.ComponentLabelKey: label.TiKVLabelVal},
+			expected: map[string]string{label.ComponentLabelKey: label.TiKVLabelVal},
+		},
+		{
+			name:     "normal",
+			custom:   map[string]string{"sidecar.istio.io/inject": "true"},
+			managed:  map[string]string{label.ComponentLabelKey: label.TiKVLabelVal},
+			expected: map[string]string{"sidecar.istio.io/inject": "true", label.ComponentLabelKey: label.TiKVLabelVal},
+		},
+		{
+			name:     "managed labels take precedence",
+			custom:   map[string]string{label.ComponentLabelKey: "foo"},
+			managed:  map[string]string{label.ComponentLabelKey: label.TiKVLabelVal},
+			expected: map[string]string{label.ComponentLabelKey: label.TiKVLabelVal},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CombineLabels(tt.custom, tt.managed)
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("unexpected (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildReadinessProbe(t *testing.T) {
+	g := NewGomegaWithT(t)
+	handler := corev1.Handler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(20160),
+		},
+	}
+
+	g.Expect(buildTCPReadinessProbe(nil, 20160)).To(BeNil())
+	g.Expect(buildReadinessProbe(nil, handler)).To(Equal(&corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: 10,
+	}))
+	g.Expect(buildTCPReadinessProbe(&v1alpha1.Probe{
+		InitialDelaySeconds: pointer.Int32Ptr(30),
+		PeriodSeconds:       pointer.Int32Ptr(20),
+		TimeoutSeconds:      pointer.Int32Ptr(5),
+		FailureThreshold:    pointer.Int32Ptr(10),
+	}, 20160)).To(Equal(&corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       20,
+		TimeoutSeconds:      5,
+		FailureThreshold:    10,
+	}))
+}
+
+func TestGetTopologySpreadConstraints(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	l := label.New().Instance("demo").TiKV()
+	g.Expect(getTopologySpreadConstraints(nil, l)).To(BeNil())
+
+	tscs := getTopologySpreadConstraints([]v1alpha1.TopologySpreadConstraint{
+		{TopologyKey: "topology.kubernetes.io/zone"},
+		{TopologyKey: "kubernetes.io/hostname"},
+	}, l)
+	g.Expect(tscs).To(HaveLen(2))
+	for i, key := range []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"} {
+		g.Expect(tscs[i].TopologyKey).To(Equal(key))
+		g.Expect(tscs[i].MaxSkew).To(Equal(int32(1)))
+		g.Expect(tscs[i].WhenUnsatisfiable).To(Equal(corev1.DoNotSchedule))
+		g.Expect(tscs[i].LabelSelector.MatchLabels).To(Equal(map[string]string(l)))
+	}
+}
+
+func TestApplyPodSpecOverlay(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  "tikv",
+				Image: "pingcap/tikv:v5.0.0",
+				Env:   []corev1.EnvVar{{Name: "TZ", Value: "UTC"}},
+			},
+		},
+		Volumes: []corev1.Volume{{Name: "config"}},
+	}
+
+	// no overlay
+	got, err := applyPodSpecOverlay(podSpec, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal(podSpec))
+
+	overlay := &runtime.RawExtension{Raw: []byte(`{
+		"containers": [{"name": "tikv", "env": [{"name": "MALLOC_CONF", "value": "prof:true"}]}],
+		"volumes": [{"name": "extra", "emptyDir": {}}],
+		"tolerations": [{"key": "dedicated", "operator": "Exists"}],
+		"enableServiceLinks": false
+	}`)}
+	got, err = applyPodSpecOverlay(podSpec, overlay)
+	g.Expect(err).NotTo(HaveOccurred())
+	// lists are merged by their keys
+	g.Expect(got.Containers).To(HaveLen(1))
+	g.Expect(got.Containers[0].Image).To(Equal("pingcap/tikv:v5.0.0"))
+	g.Expect(got.Containers[0].Env).To(ConsistOf(
+		corev1.EnvVar{Name: "TZ", Value: "UTC"},
+		corev1.EnvVar{Name: "MALLOC_CONF", Value: "prof:true"},
+	))
+	g.Expect(got.Volumes).To(HaveLen(2))
+	g.Expect(got.Tolerations).To(Equal([]corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}))
+	g.Expect(got.EnableServiceLinks).To(Equal(pointer.BoolPtr(false)))
+	// the original pod spec is not modified
+	g.Expect(podSpec.Containers[0].Env).To(HaveLen(1))
+
+	_, err = applyPodSpecOverlay(podSpec, &runtime.RawExtension{Raw: []byte(`{"containers": "invalid"}`)})
+	g.Expect(err).To(HaveOccurred())
+}
+
 func TestMemberPodName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,58 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reconcile results.
+const (
+	ReconcileSuccess = "success"
+	ReconcileRequeue = "requeue"
+	ReconcileError   = "error"
+)
+
+var (
+	ReconcileTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "controller",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Bucketed histogram of the time spent on reconciling an object",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+		}, []string{LabelController})
+
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "controller",
+			Name:      "reconcile_total",
+			Help:      "Total number of reconciliations by result",
+		}, []string{LabelController, LabelResult})
+
+	ReconcileTaskErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "controller",
+			Name:      "reconcile_task_errors_total",
+			Help:      "Total number of errors returned by each task of the reconciliation",
+		}, []string{LabelController, LabelTask})
+)
+
+// ObserveReconcile records the time spent on and the result of a reconciliation.
+func ObserveReconcile(controller, result string, seconds float64) {
+	ReconcileTime.WithLabelValues(controller).Observe(seconds)
+	ReconcileTotal.WithLabelValues(controller, result).Inc()
+}
@@ -20,11 +20,18 @@ import (
 // RegisterMetrics registers all metrics of tidb-operator.
 func RegisterMetrics() {
 	prometheus.MustRegister(ClusterSpecReplicas)
+	prometheus.MustRegister(PDClientDialFailures)
+	prometheus.MustRegister(ReconcileTime)
+	prometheus.MustRegister(ReconcileTotal)
+	prometheus.MustRegister(ReconcileTaskErrors)
 }
 
 // Label constants.
 const (
-	LabelNamespace = "namespace"
-	LabelName      = "name"
-	LabelComponent = "component"
+	LabelNamespace  = "namespace"
+	LabelName       = "name"
+	LabelComponent  = "component"
+	LabelController = "controller"
+	LabelResult     = "result"
+	LabelTask       = "task"
 )
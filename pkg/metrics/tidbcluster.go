@@ -25,4 +25,12 @@ var (
 			Name:      "spec_replicas",
 			Help:      "Desired replicas of each component in TidbCluster",
 		}, []string{LabelNamespace, LabelName, LabelComponent})
+
+	PDClientDialFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "pd_client_dial_failures_total",
+			Help:      "Total number of failures of dialing PD of each TidbCluster",
+		}, []string{LabelNamespace, LabelName})
 )
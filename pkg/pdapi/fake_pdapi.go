@@ -32,13 +32,18 @@ const (
 	GetStoreActionType                 ActionType = "GetStore"
 	DeleteStoreActionType              ActionType = "DeleteStore"
 	SetStoreStateActionType            ActionType = "SetStoreState"
+	RemoveTombStoneActionType          ActionType = "RemoveTombStone"
 	DeleteMemberByIDActionType         ActionType = "DeleteMemberByID"
 	DeleteMemberActionType             ActionType = "DeleteMember "
 	SetStoreLabelsActionType           ActionType = "SetStoreLabels"
+	SetStoreWeightActionType           ActionType = "SetStoreWeight"
 	UpdateReplicationActionType        ActionType = "UpdateReplicationConfig"
 	BeginEvictLeaderActionType         ActionType = "BeginEvictLeader"
 	EndEvictLeaderActionType           ActionType = "EndEvictLeader"
 	GetEvictLeaderSchedulersActionType ActionType = "GetEvictLeaderSchedulers"
+	GetEvictLeaderSchedulerActionType  ActionType = "GetEvictLeaderScheduler"
+	ListSchedulersActionType           ActionType = "ListSchedulers"
+	GetRegionCountByStoreActionType    ActionType = "GetRegionCountByStore"
 	GetPDLeaderActionType              ActionType = "GetPDLeader"
 	TransferPDLeaderActionType         ActionType = "TransferPDLeader"
 	GetAutoscalingPlansActionType      ActionType = "GetAutoscalingPlans"
@@ -53,10 +58,12 @@ func (nfr *NotFoundReaction) Error() string {
 }
 
 type Action struct {
-	ID          uint64
-	Name        string
-	Labels      map[string]string
-	Replication PDReplicationConfig
+	ID           uint64
+	Name         string
+	Labels       map[string]string
+	Replication  PDReplicationConfig
+	LeaderWeight float64
+	RegionWeight float64
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -169,6 +176,15 @@ func (c *FakePDClient) SetStoreState(id uint64, state string) error {
 	return nil
 }
 
+func (c *FakePDClient) RemoveTombStone() error {
+	if reaction, ok := c.reactions[RemoveTombStoneActionType]; ok {
+		action := &Action{}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
 func (c *FakePDClient) DeleteMemberByID(id uint64) error {
 	if reaction, ok := c.reactions[DeleteMemberByIDActionType]; ok {
 		action := &Action{ID: id}
@@ -197,6 +213,16 @@ func (c *FakePDClient) SetStoreLabels(storeID uint64, labels map[string]string)
 	return true, nil
 }
 
+// SetStoreWeight sets the leader and region weight of a TiKV store
+func (c *FakePDClient) SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error {
+	if reaction, ok := c.reactions[SetStoreWeightActionType]; ok {
+		action := &Action{ID: storeID, LeaderWeight: leaderWeight, RegionWeight: regionWeight}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
 // UpdateReplicationConfig updates the replication config
 func (c *FakePDClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	if reaction, ok := c.reactions[UpdateReplicationActionType]; ok {
@@ -229,11 +255,59 @@ func (c *FakePDClient) GetEvictLeaderSchedulers() ([]string, error) {
 	if reaction, ok := c.reactions[GetEvictLeaderSchedulersActionType]; ok {
 		action := &Action{}
 		result, err := reaction(action)
-		return result.([]string), err
+		if err != nil {
+			return nil, err
+		}
+		schedulers, ok := result.([]string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %T of %s reaction", result, GetEvictLeaderSchedulersActionType)
+		}
+		return schedulers, nil
 	}
 	return nil, nil
 }
 
+func (c *FakePDClient) GetEvictLeaderScheduler(storeID uint64) (string, error) {
+	if reaction, ok := c.reactions[GetEvictLeaderSchedulerActionType]; ok {
+		action := &Action{ID: storeID}
+		result, err := reaction(action)
+		if err != nil {
+			return "", err
+		}
+		name, ok := result.(string)
+		if !ok {
+			return "", fmt.Errorf("unexpected result type %T of %s reaction", result, GetEvictLeaderSchedulerActionType)
+		}
+		return name, nil
+	}
+	return "", nil
+}
+
+func (c *FakePDClient) ListSchedulers() ([]string, error) {
+	if reaction, ok := c.reactions[ListSchedulersActionType]; ok {
+		action := &Action{}
+		result, err := reaction(action)
+		if err != nil {
+			return nil, err
+		}
+		schedulers, ok := result.([]string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %T of %s reaction", result, ListSchedulersActionType)
+		}
+		return schedulers, nil
+	}
+	return nil, nil
+}
+
+func (c *FakePDClient) GetRegionCountByStore(storeID uint64) (int, error) {
+	action := &Action{ID: storeID}
+	result, err := c.fakeAPI(GetRegionCountByStoreActionType, action)
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
 func (c *FakePDClient) GetPDLeader() (*pdpb.Member, error) {
 	if reaction, ok := c.reactions[GetPDLeaderActionType]; ok {
 		action := &Action{}
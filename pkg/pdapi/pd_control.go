@@ -14,16 +14,30 @@
 package pdapi
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	"github.com/pingcap/tidb-operator/pkg/util/crypto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
 
+const (
+	// pdClientIdleTimeout is the duration after which a cached PD client which is not used is evicted,
+	// e.g. the tidb cluster has been deleted
+	pdClientIdleTimeout = 30 * time.Minute
+	// pdClientIdleConnTimeout is the duration after which an idle connection of a cached PD client is closed
+	pdClientIdleConnTimeout = 90 * time.Second
+)
+
 // Namespace is a newtype of a string
 type Namespace string
 
@@ -45,12 +59,23 @@ type defaultPDControl struct {
 	etcdmutex     sync.Mutex
 	kubeCli       kubernetes.Interface
 	pdClients     map[string]PDClient
+	pdClientMetas map[string]*pdClientMeta
 	pdEtcdClients map[string]PDEtcdClient
 }
 
+// pdClientMeta records how a cached PD client is built and when it is used,
+// the clients set by FakePDControl have no meta and are never evicted.
+type pdClientMeta struct {
+	transport *http.Transport
+	// secretVersion is the resource version of the client TLS secret the client
+	// is built with, the client is rebuilt when the secret is rotated
+	secretVersion string
+	lastUsed      time.Time
+}
+
 // NewDefaultPDControl returns a defaultPDControl instance
 func NewDefaultPDControl(kubeCli kubernetes.Interface) PDControlInterface {
-	return &defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}, pdEtcdClients: map[string]PDEtcdClient{}}
+	return &defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}, pdClientMetas: map[string]*pdClientMeta{}, pdEtcdClients: map[string]PDEtcdClient{}}
 }
 
 func (c *defaultPDControl) GetPDEtcdClient(namespace Namespace, tcName string, tlsEnabled bool) (PDEtcdClient, error) {
@@ -102,22 +127,79 @@ func (pdc *defaultPDControl) GetPeerPDClient(namespace Namespace, tcName string,
 	pdc.mutex.Lock()
 	defer pdc.mutex.Unlock()
 
-	var tlsConfig *tls.Config
-	var err error
+	now := time.Now()
+	pdc.evictIdlePDClients(now)
 
+	var tlsConfig *tls.Config
+	var secretVersion string
 	if tlsEnabled {
-		tlsConfig, err = GetTLSConfig(pdc.kubeCli, namespace, tcName, util.ClusterClientTLSSecretName(tcName))
+		secretName := util.ClusterClientTLSSecretName(tcName)
+		secret, err := pdc.kubeCli.CoreV1().Secrets(string(namespace)).Get(secretName, metav1.GetOptions{})
+		if err == nil {
+			secretVersion = secret.ResourceVersion
+			if meta, ok := pdc.pdClientMetas[clientName]; ok && meta.secretVersion == secretVersion {
+				meta.lastUsed = now
+				return pdc.pdClients[clientName]
+			}
+			tlsConfig, err = crypto.LoadTlsConfigFromSecret(secret)
+		}
 		if err != nil {
 			klog.Errorf("Unable to get tls config for tidb cluster %q in %s, pd client may not work: %v", tcName, namespace, err)
 			return &pdClient{url: clientURL, httpClient: &http.Client{Timeout: DefaultTimeout}}
 		}
+	} else if _, ok := pdc.pdClients[clientName]; ok {
+		if meta, ok := pdc.pdClientMetas[clientName]; ok {
+			meta.lastUsed = now
+		}
+		return pdc.pdClients[clientName]
+	}
 
-		return NewPDClient(clientURL, DefaultTimeout, tlsConfig)
+	// close the connections of the client built with the rotated TLS secret
+	pdc.removePDClient(clientName)
+	client, transport := newCachedPDClient(namespace, tcName, clientURL, tlsConfig)
+	pdc.pdClients[clientName] = client
+	pdc.pdClientMetas[clientName] = &pdClientMeta{
+		transport:     transport,
+		secretVersion: secretVersion,
+		lastUsed:      now,
 	}
-	if _, ok := pdc.pdClients[clientName]; !ok {
-		pdc.pdClients[clientName] = NewPDClient(clientURL, DefaultTimeout, nil)
+	return client
+}
+
+// evictIdlePDClients removes the cached PD clients which have not been used for pdClientIdleTimeout.
+func (pdc *defaultPDControl) evictIdlePDClients(now time.Time) {
+	for name, meta := range pdc.pdClientMetas {
+		if now.Sub(meta.lastUsed) > pdClientIdleTimeout {
+			klog.V(4).Infof("evict pd client %s which has been idle since %v", name, meta.lastUsed)
+			pdc.removePDClient(name)
+		}
+	}
+}
+
+func (pdc *defaultPDControl) removePDClient(clientName string) {
+	if meta, ok := pdc.pdClientMetas[clientName]; ok {
+		meta.transport.CloseIdleConnections()
+		delete(pdc.pdClientMetas, clientName)
+	}
+	delete(pdc.pdClients, clientName)
+}
+
+// newCachedPDClient returns a PD client which keeps the connections alive to be reused
+// by the following requests, the failures of dialing PD are recorded in the metrics.
+func newCachedPDClient(namespace Namespace, tcName string, clientURL string, tlsConfig *tls.Config) (PDClient, *http.Transport) {
+	dialer := &net.Dialer{Timeout: DefaultTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		IdleConnTimeout: pdClientIdleConnTimeout,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				metrics.PDClientDialFailures.WithLabelValues(string(namespace), tcName).Inc()
+			}
+			return conn, err
+		},
 	}
-	return pdc.pdClients[clientName]
+	return &pdClient{url: clientURL, httpClient: &http.Client{Timeout: DefaultTimeout, Transport: transport}}, transport
 }
 
 // pdClientKey returns the pd client key
@@ -163,7 +245,7 @@ type FakePDControl struct {
 
 func NewFakePDControl(kubeCli kubernetes.Interface) *FakePDControl {
 	return &FakePDControl{
-		defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}},
+		defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}, pdClientMetas: map[string]*pdClientMeta{}},
 	}
 }
 
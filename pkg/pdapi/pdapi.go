@@ -68,10 +68,14 @@ type PDClient interface {
 	// storeLabelsEqualNodeLabels compares store labels with node labels
 	// for historic reasons, PD stores TiKV labels as []*StoreLabel which is a key-value pair slice
 	SetStoreLabels(storeID uint64, labels map[string]string) (bool, error)
+	// SetStoreWeight sets the leader and region weight of a TiKV store
+	SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error
 	// UpdateReplicationConfig updates the replication config
 	UpdateReplicationConfig(config PDReplicationConfig) error
 	// DeleteStore deletes a TiKV store from cluster
 	DeleteStore(storeID uint64) error
+	// RemoveTombStone removes all tombstone stores from cluster
+	RemoveTombStone() error
 	// SetStoreState sets store to specified state.
 	SetStoreState(storeID uint64, state string) error
 	// DeleteMember deletes a PD member from cluster
@@ -85,6 +89,13 @@ type PDClient interface {
 	EndEvictLeader(storeID uint64) error
 	// GetEvictLeaderSchedulers gets schedulers of evict leader
 	GetEvictLeaderSchedulers() ([]string, error)
+	// GetEvictLeaderScheduler returns the evict leader scheduler of the store,
+	// empty string is returned if the scheduler does not exist
+	GetEvictLeaderScheduler(storeID uint64) (string, error)
+	// ListSchedulers lists all active schedulers from cluster
+	ListSchedulers() ([]string, error)
+	// GetRegionCountByStore returns the region count of a TiKV store reported by PD
+	GetRegionCountByStore(storeID uint64) (int, error)
 	// GetPDLeader returns pd leader
 	GetPDLeader() (*pdpb.Member, error)
 	// TransferPDLeader transfers pd leader to specified member
@@ -98,6 +109,7 @@ var (
 	membersPrefix          = "pd/api/v1/members"
 	storesPrefix           = "pd/api/v1/stores"
 	storePrefix            = "pd/api/v1/store"
+	removeTombStonePrefix  = "pd/api/v1/stores/remove-tombstone"
 	configPrefix           = "pd/api/v1/config"
 	clusterIDPrefix        = "pd/api/v1/cluster"
 	schedulersPrefix       = "pd/api/v1/schedulers"
@@ -163,6 +175,9 @@ type StoreStatus struct {
 	ReceivingSnapCount uint32            `json:"receiving_snap_count"`
 	ApplyingSnapCount  uint32            `json:"applying_snap_count"`
 	IsBusy             bool              `json:"is_busy"`
+	SlowScore          uint64            `json:"slow_score"`
+	LeaderWeight       float64           `json:"leader_weight"`
+	RegionWeight       float64           `json:"region_weight"`
 
 	StartTS         time.Time         `json:"start_ts"`
 	LastHeartbeatTS time.Time         `json:"last_heartbeat_ts"`
@@ -344,6 +359,17 @@ func (c *pdClient) GetStore(storeID uint64) (*StoreInfo, error) {
 	return storeInfo, nil
 }
 
+func (c *pdClient) GetRegionCountByStore(storeID uint64) (int, error) {
+	storeInfo, err := c.GetStore(storeID)
+	if err != nil {
+		return 0, err
+	}
+	if storeInfo.Status == nil {
+		return 0, fmt.Errorf("no status found for store %d", storeID)
+	}
+	return storeInfo.Status.RegionCount, nil
+}
+
 func (c *pdClient) DeleteStore(storeID uint64) error {
 	var exist bool
 	stores, err := c.GetStores()
@@ -382,6 +408,29 @@ func (c *pdClient) DeleteStore(storeID uint64) error {
 	return fmt.Errorf("failed to delete store %d: %v", storeID, string(body))
 }
 
+func (c *pdClient) RemoveTombStone() error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, removeTombStonePrefix)
+	req, err := http.NewRequest("DELETE", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("failed to remove tombstone stores: %v", string(body))
+}
+
 // SetStoreState sets store to specified state.
 func (c *pdClient) SetStoreState(storeID uint64, state string) error {
 	apiURL := fmt.Sprintf("%s/%s/%d/state?state=%s", c.url, storePrefix, storeID, state)
@@ -488,6 +537,27 @@ func (c *pdClient) SetStoreLabels(storeID uint64, labels map[string]string) (boo
 	return false, fmt.Errorf("failed %v to set store labels: %v", res.StatusCode, err2)
 }
 
+func (c *pdClient) SetStoreWeight(storeID uint64, leaderWeight, regionWeight float64) error {
+	apiURL := fmt.Sprintf("%s/%s/%d/weight", c.url, storePrefix, storeID)
+	data, err := json.Marshal(map[string]float64{
+		"leader": leaderWeight,
+		"region": regionWeight,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err2 := httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set store weight: %v", res.StatusCode, err2)
+}
+
 func (c *pdClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	apiURL := fmt.Sprintf("%s/%s", c.url, pdReplicationPrefix)
 	data, err := json.Marshal(config)
@@ -585,7 +655,7 @@ func (c *pdClient) EndEvictLeader(storeID uint64) error {
 	return nil
 }
 
-func (c *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
+func (c *pdClient) ListSchedulers() ([]string, error) {
 	apiURL := fmt.Sprintf("%s/%s", c.url, schedulersPrefix)
 	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
 	if err != nil {
@@ -596,6 +666,14 @@ func (c *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	return schedulers, nil
+}
+
+func (c *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
+	schedulers, err := c.ListSchedulers()
+	if err != nil {
+		return nil, err
+	}
 	var evicts []string
 	for _, scheduler := range schedulers {
 		if strings.HasPrefix(scheduler, evictSchedulerLeader) {
@@ -609,6 +687,20 @@ func (c *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
 	return evictSchedulers, nil
 }
 
+func (c *pdClient) GetEvictLeaderScheduler(storeID uint64) (string, error) {
+	schedulers, err := c.GetEvictLeaderSchedulers()
+	if err != nil {
+		return "", err
+	}
+	sName := getLeaderEvictSchedulerStr(storeID)
+	for _, s := range schedulers {
+		if s == sName {
+			return s, nil
+		}
+	}
+	return "", nil
+}
+
 // getEvictLeaderSchedulerConfig gets the config of PD scheduler "evict-leader-scheduler"
 // It's available since PD 3.1.0.
 // In the previous versions, PD API returns 404 and this function will return an error.
@@ -354,6 +354,50 @@ func TestSetStoreLabels(t *testing.T) {
 	}
 }
 
+func TestSetStoreWeight(t *testing.T) {
+	g := NewGomegaWithT(t)
+	id := uint64(1)
+	tcs := []struct {
+		caseName string
+		want     bool
+	}{{
+		caseName: "success_SetStoreWeight",
+		want:     true,
+	}, {
+		caseName: "failed_SetStoreWeight",
+		want:     false,
+	},
+	}
+
+	for _, tc := range tcs {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/%d/weight", storePrefix, id)), "check url")
+
+			weight := map[string]float64{}
+			err := readJSON(request.Body, &weight)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(weight).To(Equal(map[string]float64{"leader": 2, "region": 0.5}), "check weight")
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			if tc.want {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer svc.Close()
+
+		pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+		err := pdClient.SetStoreWeight(id, 2, 0.5)
+		if tc.want {
+			g.Expect(err).NotTo(HaveOccurred(), "check result")
+		} else {
+			g.Expect(err).To(HaveOccurred(), "check result")
+		}
+	}
+}
+
 func TestDeleteMember(t *testing.T) {
 	g := NewGomegaWithT(t)
 	name := "testMember"
@@ -634,6 +678,45 @@ func TestDeleteStore(t *testing.T) {
 	}
 }
 
+func TestRemoveTombStone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tcs := []struct {
+		caseName string
+		want     bool
+	}{{
+		caseName: "success_RemoveTombStone",
+		want:     true,
+	}, {
+		caseName: "failed_RemoveTombStone",
+		want:     false,
+	},
+	}
+
+	for _, tc := range tcs {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("DELETE"), "check method")
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", removeTombStonePrefix)), "check url")
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			if tc.want {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer svc.Close()
+
+		pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+		err := pdClient.RemoveTombStone()
+		if tc.want {
+			g.Expect(err).NotTo(HaveOccurred(), "check result")
+		} else {
+			g.Expect(err).To(HaveOccurred(), "check result")
+		}
+	}
+}
+
 func readJSON(r io.ReadCloser, data interface{}) error {
 	defer r.Close()
 
@@ -741,6 +824,61 @@ func TestGeneric(t *testing.T) {
 			wantPath:    fmt.Sprintf("/%s", schedulersPrefix),
 			checkResult: checkNoError,
 		},
+		{
+			name:   "GetEvictLeaderScheduler",
+			method: "GetEvictLeaderScheduler",
+			args: []reflect.Value{
+				reflect.ValueOf(uint64(1)),
+			},
+			resp: []byte(`
+[
+	"balance-region-scheduler",
+	"evict-leader-scheduler-1"
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s", schedulersPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "ListSchedulers",
+			method: "ListSchedulers",
+			resp: []byte(`
+[
+	"balance-hot-region-scheduler",
+	"balance-leader-scheduler",
+	"balance-region-scheduler",
+	"label-scheduler"
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s", schedulersPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "GetRegionCountByStore",
+			method: "GetRegionCountByStore",
+			args: []reflect.Value{
+				reflect.ValueOf(uint64(1)),
+			},
+			resp: []byte(`
+{
+	"store": {
+		"id": 1,
+		"state_name": "Offline"
+	},
+	"status": {
+		"region_count": 10
+	}
+}
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s/1", storePrefix),
+			checkResult: checkNoError,
+		},
 		// TODO test the fix https://github.com/pingcap/tidb-operator/pull/2809
 		// {
 		// name:        "GetEvictLeaderSchedulers for the new PD versions",
@@ -19,6 +19,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	pingcapfake "github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -143,6 +144,25 @@ func TestStableSchedulingFilter(t *testing.T) {
 				g.Expect(len(nodes)).To(Equal(3))
 			},
 		},
+		{
+			name:         "stable scheduling is disabled by the feature gates of tidb cluster",
+			instanceName: "demo",
+			pod:          makePod("demo-tidb-0", label.TiDBLabelVal),
+			tidbCluster: func() *v1alpha1.TidbCluster {
+				tc := makeTidbCluster("demo-tidb-0", "node-2")
+				tc.Spec.FeatureGates = map[string]bool{features.StableScheduling: false}
+				return tc
+			}(),
+			candicateNodes: []v1.Node{
+				makeNode("node-1"),
+				makeNode("node-2"),
+				makeNode("node-3"),
+			},
+			expectFn: func(nodes []v1.Node, err error, recorder *record.FakeRecorder) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(nodes)).To(Equal(3))
+			},
+		},
 		{
 			name:         "schedule the pod to previous node",
 			instanceName: "demo",
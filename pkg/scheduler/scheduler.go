@@ -18,7 +18,6 @@ import (
 	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
-	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/scheduler/predicates"
 	apiv1 "k8s.io/api/core/v1"
@@ -71,11 +70,11 @@ func NewScheduler(kubeCli kubernetes.Interface, cli versioned.Interface) Schedul
 		label.TiKVLabelVal: {
 			predicates.NewHA(kubeCli, cli),
 		},
-	}
-	if features.DefaultFeatureGate.Enabled(features.StableScheduling) {
-		predicatesByComponent[label.TiDBLabelVal] = []predicates.Predicate{
+		// StableScheduling can be overridden by spec.featureGates of a cluster,
+		// so it's checked by the predicate for each cluster.
+		label.TiDBLabelVal: {
 			predicates.NewStableScheduling(kubeCli, cli),
-		}
+		},
 	}
 	return &scheduler{
 		predicates: predicatesByComponent,
@@ -34,6 +34,40 @@ const (
 	TiDBUnhealthy = "TiDBUnhealthy"
 	// TiFlashStoreNotUp is added when one of tiflash stores is not up.
 	TiFlashStoreNotUp = "TiFlashStoreNotUp"
+	// TiKVSlowStoreDetected is added when one of tikv stores is reported as slow by PD.
+	TiKVSlowStoreDetected = "TiKVSlowStoreDetected"
+	// TiKVNoSlowStore is added when no tikv store is reported as slow by PD.
+	TiKVNoSlowStore = "TiKVNoSlowStore"
+	// TiKVStoreDiskUsageHigh is added when the disk usage of one of tikv stores exceeds the threshold.
+	TiKVStoreDiskUsageHigh = "TiKVStoreDiskUsageHigh"
+	// TiKVStoreDiskUsageNormal is added when the disk usage of all tikv stores is below the threshold.
+	TiKVStoreDiskUsageNormal = "TiKVStoreDiskUsageNormal"
+	// TiDBSQLReady is added when all tidb instances can execute SQL.
+	TiDBSQLReady = "TiDBSQLReady"
+	// TiDBSQLNotReady is added when one of tidb instances can not execute SQL.
+	TiDBSQLNotReady = "TiDBSQLNotReady"
+	// TiKVStoresWarmedUp is added when the tikv stores are balanced after scaling.
+	TiKVStoresWarmedUp = "TiKVStoresWarmedUp"
+	// TiKVStoresWarmingUp is added when the tikv stores are being scaled or are not balanced yet.
+	TiKVStoresWarmingUp = "TiKVStoresWarmingUp"
+	// TiKVWarmUpTimeout is added when the tikv stores are not balanced in time after scaling.
+	TiKVWarmUpTimeout = "TiKVWarmUpTimeout"
+	// ComponentsUpgrading is added when one of the components is being upgraded.
+	ComponentsUpgrading = "ComponentsUpgrading"
+	// ComponentsScaling is added when one of the components is being scaled.
+	ComponentsScaling = "ComponentsScaling"
+	// ComponentsUpToDate is added when all components are up to date and not being scaled.
+	ComponentsUpToDate = "ComponentsUpToDate"
+	// Paused is added when the tidb cluster is paused.
+	Paused = "Paused"
+	// NotPaused is added when the tidb cluster is not paused.
+	NotPaused = "NotPaused"
+	// ComponentsSuspended is added when one of the components is suspended.
+	ComponentsSuspended = "ComponentsSuspended"
+	// FailoverInProgress is added when one of the members has failed and is being failed over.
+	FailoverInProgress = "FailoverInProgress"
+	// NoFailure is added when no member has failed.
+	NoFailure = "NoFailure"
 )
 
 // NewTidbClusterCondition creates a new tidbcluster condition.
@@ -60,10 +94,12 @@ func GetTidbClusterCondition(status v1alpha1.TidbClusterStatus, condType v1alpha
 }
 
 // SetTidbClusterCondition updates the tidb cluster to include the provided condition. If the condition that
-// we are about to add already exists and has the same status and reason then we are not going to update.
+// we are about to add already exists and has the same status, reason and observed generation then we are
+// not going to update.
 func SetTidbClusterCondition(status *v1alpha1.TidbClusterStatus, condition v1alpha1.TidbClusterCondition) {
 	currentCond := GetTidbClusterCondition(*status, condition.Type)
-	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
+	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason &&
+		currentCond.ObservedGeneration == condition.ObservedGeneration {
 		return
 	}
 	// Do not update lastTransitionTime if the status of the condition doesn't change.
@@ -74,6 +110,11 @@ func SetTidbClusterCondition(status *v1alpha1.TidbClusterStatus, condition v1alp
 	status.Conditions = append(newConditions, condition)
 }
 
+// RemoveTidbClusterCondition removes the condition with the provided type from the tidb cluster status.
+func RemoveTidbClusterCondition(status *v1alpha1.TidbClusterStatus, condType v1alpha1.TidbClusterConditionType) {
+	status.Conditions = filterOutCondition(status.Conditions, condType)
+}
+
 // filterOutCondition returns a new slice of tidbcluster conditions without conditions with the provided type.
 func filterOutCondition(conditions []v1alpha1.TidbClusterCondition, condType v1alpha1.TidbClusterConditionType) []v1alpha1.TidbClusterCondition {
 	var newConditions []v1alpha1.TidbClusterCondition
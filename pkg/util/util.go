@@ -357,7 +357,7 @@ func BuildStorageVolumeAndVolumeMount(storageVolumes []v1alpha1.StorageVolume, d
 			pvcNameInVCT := fmt.Sprintf("%s-%s", memberType.String(), storageVolume.Name)
 			volumeClaims = append(volumeClaims, VolumeClaimTemplate(storageRequest, pvcNameInVCT, tmpStorageClass))
 			volMounts = append(volMounts, corev1.VolumeMount{
-				Name: pvcNameInVCT, MountPath: storageVolume.MountPath,
+				Name: pvcNameInVCT, MountPath: storageVolume.MountPath, SubPath: storageVolume.SubPath,
 			})
 		}
 	}
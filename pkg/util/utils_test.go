@@ -678,6 +678,26 @@ func TestBuildAdditionalVolumeAndVolumeMount(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "tidb spec storageVolumes with subPath",
+			storageVolumes: []v1alpha1.StorageVolume{
+				{
+					Name:        "tmp",
+					StorageSize: "2Gi",
+					MountPath:   "/var/lib/tidb-tmp",
+					SubPath:     "tmp-storage",
+				}},
+			memberType: v1alpha1.TiDBMemberType,
+			testResult: func(volMounts []corev1.VolumeMount, volumeClaims []corev1.PersistentVolumeClaim) {
+				g := NewGomegaWithT(t)
+				g.Expect(volumeClaims).To(HaveLen(1))
+				g.Expect(volMounts).To(Equal([]corev1.VolumeMount{
+					{
+						Name: fmt.Sprintf("%s-%s", v1alpha1.TiDBMemberType, "tmp"), MountPath: "/var/lib/tidb-tmp", SubPath: "tmp-storage",
+					},
+				}))
+			},
+		},
 		{
 			name:             "tikv spec multiple storageVolumes",
 			storageClassName: pointer.StringPtr("ns2"),
@@ -0,0 +1,88 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+// priorityClassNames returns the priority classes used by the components of a TidbCluster,
+// keyed by the field path of the first component using it.
+func priorityClassNames(tc *v1alpha1.TidbCluster) map[string]*field.Path {
+	specPath := field.NewPath("spec")
+	accessors := map[string]v1alpha1.ComponentAccessor{}
+	if tc.Spec.PD != nil {
+		accessors["pd"] = tc.BasePDSpec()
+	}
+	if tc.Spec.TiKV != nil {
+		accessors["tikv"] = tc.BaseTiKVSpec()
+	}
+	if tc.Spec.TiDB != nil {
+		accessors["tidb"] = tc.BaseTiDBSpec()
+	}
+	if tc.Spec.TiFlash != nil {
+		accessors["tiflash"] = tc.BaseTiFlashSpec()
+	}
+	if tc.Spec.TiCDC != nil {
+		accessors["ticdc"] = tc.BaseTiCDCSpec()
+	}
+	if pump, ok := tc.BasePumpSpec(); ok {
+		accessors["pump"] = pump
+	}
+
+	names := map[string]*field.Path{}
+	for _, component := range []string{"pd", "tikv", "tidb", "tiflash", "ticdc", "pump"} {
+		accessor, ok := accessors[component]
+		if !ok {
+			continue
+		}
+		name := accessor.PriorityClassName()
+		if name == nil || *name == "" {
+			continue
+		}
+		if _, ok := names[*name]; !ok {
+			names[*name] = specPath.Child(component, "priorityClassName")
+		}
+	}
+	return names
+}
+
+// validatePriorityClasses makes sure the priority classes used by the components of a TidbCluster exist.
+// On updating only the newly used ones are checked, so the cluster can still be updated after a
+// priority class in use is deleted. Errors other than NotFound, e.g. the webhook is not allowed to
+// get priority classes, are only logged and do not reject the request.
+func validatePriorityClasses(kubeCli kubernetes.Interface, tc, old *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var oldNames map[string]*field.Path
+	if old != nil {
+		oldNames = priorityClassNames(old)
+	}
+	for name, fldPath := range priorityClassNames(tc) {
+		if _, ok := oldNames[name]; ok {
+			continue
+		}
+		_, err := kubeCli.SchedulingV1().PriorityClasses().Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(fldPath, name))
+		} else if err != nil {
+			klog.Warningf("failed to get priority class %s for %s, skip validating it, error: %v", name, fldPath, err)
+		}
+	}
+	return allErrs
+}
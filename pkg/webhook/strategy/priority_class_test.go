@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+)
+
+func TestValidatePriorityClasses(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := fake.NewSimpleClientset(&schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "high"},
+	})
+	tc := &v1alpha1.TidbCluster{
+		Spec: v1alpha1.TidbClusterSpec{
+			PriorityClassName: pointer.StringPtr("high"),
+			PD:                &v1alpha1.PDSpec{},
+			TiKV:              &v1alpha1.TiKVSpec{},
+		},
+	}
+
+	// the cluster level priority class is inherited by the components
+	g.Expect(priorityClassNames(tc)).To(Equal(map[string]*field.Path{
+		"high": field.NewPath("spec", "pd", "priorityClassName"),
+	}))
+	g.Expect(validatePriorityClasses(kubeCli, tc, nil)).To(BeEmpty())
+
+	tc.Spec.TiKV.PriorityClassName = pointer.StringPtr("missing")
+	errs := validatePriorityClasses(kubeCli, tc, nil)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotFound))
+	g.Expect(errs[0].Field).To(Equal("spec.tikv.priorityClassName"))
+
+	// the priority classes already in use are not checked on updating
+	old := tc.DeepCopy()
+	g.Expect(validatePriorityClasses(kubeCli, tc, old)).To(BeEmpty())
+
+	// errors other than NotFound do not reject the cluster
+	kubeCli.PrependReactor("get", "priorityclasses", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schedulingv1.Resource("priorityclasses"), "missing", fmt.Errorf("forbidden"))
+	})
+	g.Expect(validatePriorityClasses(kubeCli, tc, nil)).To(BeEmpty())
+}
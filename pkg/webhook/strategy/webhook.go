@@ -18,10 +18,13 @@ import (
 	"encoding/json"
 
 	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/webhook/util"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 )
@@ -29,6 +32,8 @@ import (
 // StrategyAdmissionHook is a admission webhook based on the registered strategies in the given registry
 type StrategyAdmissionHook struct {
 	registry *StrategyRegistry
+	// kubeCli is used to validate the references to other resources, it's nil before initialized
+	kubeCli kubernetes.Interface
 }
 
 var _ apiserver.ValidatingAdmissionHook = &StrategyAdmissionHook{}
@@ -71,16 +76,21 @@ func (w *StrategyAdmissionHook) Validate(ar *admissionv1beta1.AdmissionRequest)
 		return util.ARFail(err)
 	}
 	var allErr field.ErrorList
+	var old runtime.Object
 	if ar.Operation == admissionv1beta1.Create {
 		allErr = s.Validate(context.TODO(), obj)
 	} else {
-		old := s.NewObject()
+		old = s.NewObject()
 		if err := json.Unmarshal(ar.OldObject.Raw, old); err != nil {
 			klog.Errorf("admission validating failed: cannot unmarshal %s to %T", ar.Kind, old)
 			return util.ARFail(err)
 		}
 		allErr = s.ValidateUpdate(context.TODO(), obj, old)
 	}
+	if tc, ok := obj.(*v1alpha1.TidbCluster); ok && w.kubeCli != nil {
+		oldTc, _ := old.(*v1alpha1.TidbCluster)
+		allErr = append(allErr, validatePriorityClasses(w.kubeCli, tc, oldTc)...)
+	}
 	if len(allErr) > 0 {
 		return util.ARFail(allErr.ToAggregate())
 	}
@@ -119,5 +129,10 @@ func (w *StrategyAdmissionHook) Admit(ar *admissionv1beta1.AdmissionRequest) *ad
 }
 
 func (w *StrategyAdmissionHook) Initialize(cfg *rest.Config, stopCh <-chan struct{}) error {
+	kubeCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	w.kubeCli = kubeCli
 	return nil
 }
@@ -46,6 +46,22 @@ func (p *proxiedTiDBClient) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*c
 	panic("implement when necessary")
 }
 
+func (p *proxiedTiDBClient) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	panic("implement when necessary")
+}
+
+func (p *proxiedTiDBClient) SetServerLabels(tc *v1alpha1.TidbCluster, ordinal int32, labels map[string]string) error {
+	panic("implement when necessary")
+}
+
+func (p *proxiedTiDBClient) CheckSQL(tc *v1alpha1.TidbCluster, ordinal int32, user, password string) error {
+	panic("implement when necessary")
+}
+
+func (p *proxiedTiDBClient) ReloadTLS(tc *v1alpha1.TidbCluster, ordinal int32, user, password string, cert []byte) (bool, error) {
+	panic("implement when necessary")
+}
+
 func (p *proxiedTiDBClient) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	tcName := tc.GetName()
 	ns := tc.GetNamespace()